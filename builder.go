@@ -2,13 +2,24 @@ package deptree
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
 // DepTreeBuilder builds a dependency tree based on the strings representing node ids.
 type DepTreeBuilder struct {
-	isIntegral bool
-	deps       map[string][]string
+	isIntegral         bool
+	indexed            bool
+	allowEmptyPatterns bool
+	deps               map[string][]string
+	conflicts          map[string][]string
+	costs              map[string]float64
+	typed              map[string][]TypedDep
+	tags               map[string][]string
+	patterns           map[string][]string
+	priorities         map[string]int
+	ordering           Ordering
+	condensing         bool
 }
 
 // NewDepTreeBuilder returns a new dependency tree builder.
@@ -18,34 +29,187 @@ func NewDepTreeBuilder() *DepTreeBuilder {
 	}
 }
 
-// AddDeps adds dependencies to the dependency tree.
+// AddDeps adds dependencies to the dependency tree. Dependencies already added to the node are skipped.
 func (dtb *DepTreeBuilder) AddDeps(node string, deps ...string) {
 	if _, ok := dtb.deps[node]; !ok {
-		dtb.deps[node] = make([]string, 0)
+		dtb.deps[node] = make([]string, 0, len(deps))
+	}
+	if len(deps) > 0 {
+		known := make(map[string]bool, len(dtb.deps[node])+len(deps))
+		for _, dep := range dtb.deps[node] {
+			known[dep] = true
+		}
+		for _, dep := range deps {
+			if !known[dep] {
+				known[dep] = true
+				dtb.deps[node] = append(dtb.deps[node], dep)
+			}
+		}
 	}
-	dtb.deps[node] = append(dtb.deps[node], deps...)
 	dtb.isIntegral = false
 }
 
+// ReplaceDeps replaces all dependencies of the node including the pattern dependencies. The node is added if it
+// doesn't exist. The new dependencies are untyped.
+func (dtb *DepTreeBuilder) ReplaceDeps(node string, deps ...string) {
+	dtb.deps[node] = make([]string, 0)
+	delete(dtb.typed, node)
+	delete(dtb.patterns, node)
+	dtb.AddDeps(node, deps...)
+}
+
+// RemoveDep removes the dependency of the node. Error is returned if the node doesn't depend on the dependency.
+func (dtb *DepTreeBuilder) RemoveDep(node, dep string) error {
+	deps := make([]string, 0)
+	for _, d := range dtb.deps[node] {
+		if d != dep {
+			deps = append(deps, d)
+		}
+	}
+	if len(deps) == len(dtb.deps[node]) {
+		return fmt.Errorf("%w: dependency \"%s\" of \"%s\"", ErrNotFound, dep, node)
+	}
+	dtb.deps[node] = deps
+	if _, ok := dtb.typed[node]; ok {
+		dtb.typed[node] = withoutTypedDeps(dtb.typed[node], deps)
+	}
+	return nil
+}
+
+// RemoveMode decides what happens when a removed node is still required by other nodes.
+type RemoveMode int
+
+const (
+	// RemoveStrict makes the removal fail if the node is still required by other nodes.
+	RemoveStrict RemoveMode = iota
+	// RemoveCascade removes the nodes requiring the removed node as well, directly or transitively.
+	RemoveCascade
+)
+
+// RemoveNode removes the node with its dependencies, conflicts and cost. Error is returned if the node doesn't exist or,
+// in RemoveStrict mode, if other nodes still depend on it.
+func (dtb *DepTreeBuilder) RemoveNode(node string, mode RemoveMode) error {
+	_, err := dtb.removeNode(node, mode)
+	return err
+}
+
+// removeNode removes the node and returns ids of all removed nodes.
+func (dtb *DepTreeBuilder) removeNode(node string, mode RemoveMode) ([]string, error) {
+	if _, ok := dtb.deps[node]; !ok {
+		return nil, fmt.Errorf("%w: node \"%s\"", ErrNotFound, node)
+	}
+	dependents := make(map[string][]string)
+	for n, deps := range dtb.deps {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], n)
+		}
+	}
+	if mode == RemoveStrict && len(dependents[node]) > 0 {
+		required := append([]string{}, dependents[node]...)
+		sort.Strings(required)
+		return nil, fmt.Errorf("%w: node \"%s\" is required by \"%s\"", ErrIntegrity, node,
+			strings.Join(required, "\", \""))
+	}
+	removed := sortedKeys(reachable(dependents, node))
+	for _, n := range removed {
+		delete(dtb.deps, n)
+		dtb.dropMetadata(n)
+	}
+	return removed, nil
+}
+
+// dropMetadata removes everything but the dependencies known about the node.
+func (dtb *DepTreeBuilder) dropMetadata(node string) {
+	delete(dtb.conflicts, node)
+	delete(dtb.costs, node)
+	delete(dtb.typed, node)
+	delete(dtb.tags, node)
+	delete(dtb.patterns, node)
+	delete(dtb.priorities, node)
+}
+
+// AddConflicts declares that the node cannot be used together with any of the conflicting nodes. The conflict is
+// symmetric, it is enough to declare it for one of the nodes. See DepTree.CheckConflicts for more details.
+func (dtb *DepTreeBuilder) AddConflicts(node string, conflicts ...string) {
+	if dtb.conflicts == nil {
+		dtb.conflicts = make(map[string][]string)
+	}
+	dtb.conflicts[node] = append(dtb.conflicts[node], conflicts...)
+}
+
+// SetCost sets the cost of the node used by the critical path analysis. Nodes without the cost weigh DefaultCost.
+func (dtb *DepTreeBuilder) SetCost(node string, cost float64) {
+	if dtb.costs == nil {
+		dtb.costs = make(map[string]float64)
+	}
+	dtb.costs[node] = cost
+}
+
 // Build builds a dependency tree from the dependency tree builder. Error is returned if the dependency tree
 // contains a cycle or violates an integrity. The integrity is violated if a node for a dependency is not added to the
 // builder. It means that if you provide "B" as dependency for "A", then you need to provide "B" with no dependencies.
 // You can also call function ForceIntegrity() that automatically adds missing nodes to the builder.
+// If conflicts are declared, a ConflictError is returned when a node requires two conflicting nodes itself.
+// Pattern dependencies are expanded before the checks, see AddPatternDeps.
 func (dtb *DepTreeBuilder) Build() (*DepTree, error) {
-	if err := dtb.integrityCheck(); err != nil {
+	deps, via, err := dtb.expandPatterns()
+	if err != nil {
 		return nil, err
 	}
-	if err := dtb.cyclesCheck(); err != nil {
+	if err := integrityCheck(deps); err != nil {
 		return nil, err
 	}
+	if !dtb.condensing {
+		if err := cyclesCheck(deps, via); err != nil {
+			return nil, err
+		}
+	}
+	tree := &DepTree{deps: deps, via: via, ordering: dtb.ordering}
+	if dtb.condensing {
+		tree.condense()
+	}
+	if len(dtb.conflicts) > 0 {
+		tree.conflicts = copyDeps(dtb.conflicts)
+		if err := tree.selfConflictsCheck(); err != nil {
+			return nil, err
+		}
+	}
+	if len(dtb.typed) > 0 {
+		tree.typed = make(map[string][]TypedDep, len(dtb.typed))
+		for k, v := range dtb.typed {
+			tree.typed[k] = append([]TypedDep{}, v...)
+		}
+	}
+	if len(dtb.tags) > 0 {
+		tree.tags = copyDeps(dtb.tags)
+	}
+	if len(dtb.priorities) > 0 {
+		tree.priorities = make(map[string]int, len(dtb.priorities))
+		for k, v := range dtb.priorities {
+			tree.priorities[k] = v
+		}
+	}
+	if dtb.indexed && len(tree.groups) == 0 {
+		tree.index = newReachIndex(tree.deps)
+	}
+	if len(dtb.costs) > 0 {
+		tree.costs = make(map[string]float64)
+		for k, v := range dtb.costs {
+			tree.costs[k] = v
+		}
+	}
+	return tree, nil
+}
+
+func copyDeps(deps map[string][]string) map[string][]string {
 	newMap := make(map[string][]string)
-	for k, v := range dtb.deps {
+	for k, v := range deps {
 		newMap[k] = make([]string, len(v))
 		for i, d := range v {
 			newMap[k][i] = d
 		}
 	}
-	return &DepTree{deps: newMap}, nil
+	return newMap
 }
 
 // ForceIntegrity adds missing nodes to the dependency tree builder. You can call this function if you don't want to
@@ -61,41 +225,53 @@ func (dtb *DepTreeBuilder) ForceIntegrity() {
 	}
 }
 
-func (dtb *DepTreeBuilder) integrityCheck() error {
-	for _, children := range dtb.deps {
+func integrityCheck[K comparable](deps map[K][]K) error {
+	for _, children := range deps {
 		for _, child := range children {
-			if _, ok := dtb.deps[child]; !ok {
-				return fmt.Errorf("%w: missing dependency \"%s\"", ErrIntegrity, child)
+			if _, ok := deps[child]; !ok {
+				return fmt.Errorf("%w: missing dependency \"%v\"", ErrIntegrity, child)
 			}
 		}
 	}
 	return nil
 }
 
-func (dtb *DepTreeBuilder) cyclesCheck() error {
-	for node, _ := range dtb.deps {
-		if ch, err := dtb.cycleCheckFor(node, node); err != nil {
-			return fmt.Errorf("%w: %s", err, strings.Join(ch, "->"))
+func cyclesCheck(deps map[string][]string, via map[string]map[string]string) error {
+	if ch := findCycle(deps, sortedKeys(deps)); ch != nil {
+		return fmt.Errorf("%w: cycle detected: %s", ErrIntegrity, chainString(ch, via))
+	}
+	return nil
+}
+
+// findCycle returns the chain leading to a cycle reachable from the given nodes, or nil if there is no such cycle.
+// The nodes are searched in the given order.
+func findCycle[K comparable](deps map[K][]K, nodes []K) []K {
+	checked := make(map[K]bool)
+	for _, node := range nodes {
+		if ch := cycleCheckFor(deps, node, make(map[K]bool), checked); ch != nil {
+			return ch
 		}
 	}
 	return nil
 }
 
-func (dtb *DepTreeBuilder) cycleCheckFor(top, current string) ([]string, error) {
-	chain := []string{current}
-	if deps, ok := dtb.deps[current]; ok {
-		for _, dep := range deps {
-			if top == dep {
-				return chain, fmt.Errorf("%w: cycle detected", ErrIntegrity)
-			}
-			ch, err := dtb.cycleCheckFor(top, dep)
-			if ch != nil {
-				chain = append(chain, ch...)
-			}
-			if err != nil {
-				return chain, err
-			}
+// cycleCheckFor searches for a cycle reachable from the current node. The visiting nodes are on the current path,
+// the checked nodes don't reach any cycle. The returned chain ends with the cycle, the first and the last node of
+// the cycle are the same.
+func cycleCheckFor[K comparable](graph map[K][]K, current K, visiting, checked map[K]bool) []K {
+	if checked[current] {
+		return nil
+	}
+	visiting[current] = true
+	for _, dep := range graph[current] {
+		if visiting[dep] {
+			return []K{current, dep}
+		}
+		if ch := cycleCheckFor(graph, dep, visiting, checked); ch != nil {
+			return append([]K{current}, ch...)
 		}
 	}
-	return chain, nil
+	delete(visiting, current)
+	checked[current] = true
+	return nil
 }
@@ -1,8 +1,11 @@
 package deptree
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestDepTreeBuilder_AddDeps(t *testing.T) {
@@ -53,6 +56,17 @@ func TestDepTreeBuilder_AddDeps(t *testing.T) {
 				"d": {"b", "a"},
 			},
 		},
+		{
+			name:   "AddDeps skips duplicates",
+			fields: fields{deps: map[string][]string{"a": {"b", "c"}}},
+			args: args{
+				node: "a",
+				deps: []string{"c", "d", "d"},
+			},
+			result: map[string][]string{
+				"a": {"b", "c", "d"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -101,6 +115,12 @@ func TestDepTreeBuilder_Build(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:    "Build with cycle not reaching first node",
+			fields:  fields{deps: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}}},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -118,3 +138,101 @@ func TestDepTreeBuilder_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestDepTreeBuilder_BuildCycleChain(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("a", "b")
+	builder.AddDeps("b", "c")
+	builder.AddDeps("c", "b")
+	want := "integrity error: cycle detected: a->b->c->b"
+	if _, err := builder.Build(); err == nil || err.Error() != want {
+		t.Errorf("Build() error = %v, want %v", err, want)
+	}
+}
+
+func TestDepTreeBuilder_AddDepsLargeList(t *testing.T) {
+	const n = 100000
+	deps := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		deps = append(deps, fmt.Sprint(i%(n/2)))
+	}
+	builder := NewDepTreeBuilder()
+	start := time.Now()
+	builder.AddDeps("a", deps[:n/4]...)
+	builder.AddDeps("a", deps...)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("AddDeps() took %v for %d dependencies, want them deduplicated in linear time", elapsed, n)
+	}
+	if !reflect.DeepEqual(builder.deps["a"], deps[:n/2]) {
+		t.Errorf("AddDeps() kept %d dependencies, want %d", len(builder.deps["a"]), n/2)
+	}
+}
+
+func TestDepTreeBuilder_RemoveNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    string
+		mode    RemoveMode
+		want    map[string][]string
+		wantErr error
+	}{
+		{
+			name: "remove top",
+			node: "a",
+			mode: RemoveStrict,
+			want: map[string][]string{"b": {"c"}, "c": {}, "d": {}},
+		},
+		{
+			name:    "remove required node",
+			node:    "c",
+			mode:    RemoveStrict,
+			want:    map[string][]string{"a": {"b"}, "b": {"c"}, "c": {}, "d": {}},
+			wantErr: ErrIntegrity,
+		},
+		{
+			name: "cascade",
+			node: "c",
+			mode: RemoveCascade,
+			want: map[string][]string{"d": {}},
+		},
+		{
+			name:    "not existent",
+			node:    "x",
+			mode:    RemoveCascade,
+			want:    map[string][]string{"a": {"b"}, "b": {"c"}, "c": {}, "d": {}},
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dtb := &DepTreeBuilder{
+				deps:  map[string][]string{"a": {"b"}, "b": {"c"}, "c": {}, "d": {}},
+				costs: map[string]float64{"a": 2},
+			}
+			if err := dtb.RemoveNode(tt.node, tt.mode); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemoveNode() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(dtb.deps, tt.want) {
+				t.Errorf("RemoveNode() = %v, want %v", dtb.deps, tt.want)
+			}
+			if _, ok := dtb.costs["a"]; ok != (dtb.deps["a"] != nil) {
+				t.Errorf("RemoveNode() left cost of removed node")
+			}
+		})
+	}
+}
+
+func TestDepTreeBuilder_RemoveDep(t *testing.T) {
+	dtb := &DepTreeBuilder{deps: map[string][]string{"a": {"b", "c"}, "b": {}, "c": {}}}
+	if err := dtb.RemoveDep("a", "b"); err != nil {
+		t.Fatalf("RemoveDep() error = %v", err)
+	}
+	if err := dtb.RemoveDep("a", "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveDep() error = %v, want %v", err, ErrNotFound)
+	}
+	dtb.ReplaceDeps("b", "c", "c")
+	want := map[string][]string{"a": {"c"}, "b": {"c"}, "c": {}}
+	if !reflect.DeepEqual(dtb.deps, want) {
+		t.Errorf("RemoveDep() = %v, want %v", dtb.deps, want)
+	}
+}
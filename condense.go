@@ -0,0 +1,206 @@
+package deptree
+
+import "sort"
+
+// CondenseCycles makes Build accept the cycles. The nodes of every cycle, or more precisely of every strongly
+// connected component, are condensed into a group listed together by ListAsc and ListDesc, ordered by the ids.
+// The groups are ordered like single nodes, a group is represented by its least id for the ordering. CriticalPath and
+// Schedule treat a group as a single node with the total cost of its nodes. The reachability index is not built for
+// a tree with cycles. A MutableDepTree built this way accepts new cycles too and condenses the groups again after
+// every modification.
+func (dtb *DepTreeBuilder) CondenseCycles() {
+	dtb.condensing = true
+}
+
+// CondenseCycles makes Build accept the cycles. See DepTreeBuilder.CondenseCycles for more details.
+func (dtb *NDepTreeBuilder[N]) CondenseCycles() {
+	dtb.builder.CondenseCycles()
+}
+
+// CondenseCycles makes Build accept the cycles. See DepTreeBuilder.CondenseCycles for more details.
+func (dtb *IDepTreeBuilder) CondenseCycles() {
+	(*NDepTreeBuilder[Node])(dtb).CondenseCycles()
+}
+
+// Cycles returns the groups of the nodes condensed because of the cycles, see CondenseCycles. Every group is sorted,
+// the groups are sorted by their first ids. A node depending on itself is a group of one node.
+func (dt *DepTree) Cycles() [][]string {
+	result := make([][]string, 0, len(dt.groups))
+	for _, rep := range sortedKeys(dt.groups) {
+		result = append(result, append([]string{}, dt.groups[rep]...))
+	}
+	return result
+}
+
+// ListAscGroups works like ListAsc, but returns the nodes of the same cycle together as a group. A node not in
+// a cycle is a group of one node. See CondenseCycles for more details.
+func (dt *DepTree) ListAscGroups(top ...string) [][]string {
+	return dt.orderGroups(dt.lookup, top)
+}
+
+// ListDescGroups works like ListDesc, but returns the nodes of the same cycle together as a group. The groups and
+// their nodes are in the reversed order of ListAscGroups.
+func (dt *DepTree) ListDescGroups(top ...string) [][]string {
+	groups := dt.ListAscGroups(top...)
+	for i, j := 0, len(groups)-1; i < j; i, j = i+1, j-1 {
+		groups[i], groups[j] = groups[j], groups[i]
+	}
+	for _, group := range groups {
+		sort.Sort(sort.Reverse(sort.StringSlice(group)))
+	}
+	return groups
+}
+
+// condense finds the cycles of the tree and groups their nodes.
+func (dt *DepTree) condense() {
+	dt.condensed = true
+	dt.groups = nil
+	dt.group = nil
+	for _, component := range stronglyConnected(dt.deps) {
+		if len(component) == 1 && !contains(dt.deps[component[0]], component[0]) {
+			continue
+		}
+		sort.Strings(component)
+		if dt.groups == nil {
+			dt.groups = make(map[string][]string)
+			dt.group = make(map[string]string)
+		}
+		dt.groups[component[0]] = component
+		for _, node := range component {
+			dt.group[node] = component[0]
+		}
+	}
+}
+
+// orderGroups returns the groups of the nodes required by the tops in ascending order of the tree's ordering.
+func (dt *DepTree) orderGroups(lookup func(node string) ([]string, bool), top []string) [][]string {
+	if len(dt.groups) == 0 {
+		order := dt.orderNodes(lookup, top)
+		result := make([][]string, len(order))
+		for i, node := range order {
+			result[i] = []string{node}
+		}
+		return result
+	}
+	reps := make([]string, len(top))
+	for i, node := range top {
+		reps[i] = dt.representative(node)
+	}
+	order := dt.orderNodes(dt.groupLookup(lookup), reps)
+	result := make([][]string, len(order))
+	for i, rep := range order {
+		result[i] = append([]string{}, dt.members(rep)...)
+	}
+	return result
+}
+
+// representative returns the least id of the group of the node or the node itself if it's not in a cycle.
+func (dt *DepTree) representative(node string) string {
+	if rep, ok := dt.group[node]; ok {
+		return rep
+	}
+	return node
+}
+
+// members returns the nodes of the group represented by the id or the node itself if it's not in a cycle.
+func (dt *DepTree) members(rep string) []string {
+	if group, ok := dt.groups[rep]; ok {
+		return group
+	}
+	return []string{rep}
+}
+
+// groupCost returns the total cost of the nodes of the group represented by the id.
+func (dt *DepTree) groupCost(rep string) float64 {
+	cost := 0.0
+	for _, member := range dt.members(rep) {
+		cost += dt.cost(member)
+	}
+	return cost
+}
+
+// condensedOrder returns the ids representing the groups required by the tops in ascending order, see
+// ListAscGroups.
+func (dt *DepTree) condensedOrder(top []string) []string {
+	groups := dt.ListAscGroups(top...)
+	result := make([]string, len(groups))
+	for i, group := range groups {
+		result[i] = group[0]
+	}
+	return result
+}
+
+// condensedLookup returns the lookup of the condensed graph. It's the lookup of the tree if there are no cycles.
+func (dt *DepTree) condensedLookup() func(node string) ([]string, bool) {
+	if len(dt.groups) == 0 {
+		return dt.lookup
+	}
+	return dt.groupLookup(dt.lookup)
+}
+
+// groupLookup returns the lookup of the condensed graph, the groups are represented by their least ids.
+func (dt *DepTree) groupLookup(lookup func(node string) ([]string, bool)) func(node string) ([]string, bool) {
+	return func(rep string) ([]string, bool) {
+		result := make([]string, 0)
+		for _, member := range dt.members(rep) {
+			deps, ok := lookup(member)
+			if !ok {
+				return nil, false
+			}
+			for _, dep := range deps {
+				if r := dt.representative(dep); r != rep && !contains(result, r) {
+					result = append(result, r)
+				}
+			}
+		}
+		return result, true
+	}
+}
+
+// stronglyConnected returns the strongly connected components of the graph using Tarjan's algorithm. The dependencies
+// of a component come before it.
+func stronglyConnected(deps map[string][]string) [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := make([]string, 0)
+	result := make([][]string, 0)
+	var connect func(node string)
+	connect = func(node string) {
+		index[node] = len(index)
+		low[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, dep := range deps[node] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if _, visited := index[dep]; !visited {
+				connect(dep)
+				low[node] = min(low[node], low[dep])
+			} else if onStack[dep] {
+				low[node] = min(low[node], index[dep])
+			}
+		}
+		if low[node] != index[node] {
+			return
+		}
+		component := make([]string, 0)
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		result = append(result, component)
+	}
+	for _, node := range sortedKeys(deps) {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+	return result
+}
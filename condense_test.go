@@ -0,0 +1,219 @@
+package deptree
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func cyclicBuilder() *DepTreeBuilder {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "b", "tool")
+	builder.AddDeps("a", "b", "lib")
+	builder.AddDeps("b", "a")
+	builder.AddDeps("lib")
+	builder.AddDeps("tool", "tool")
+	return builder
+}
+
+func TestDepTreeBuilder_CondenseCycles(t *testing.T) {
+	if _, err := cyclicBuilder().Build(); !errors.Is(err, ErrIntegrity) {
+		t.Fatalf("Build() error = %v, want %v", err, ErrIntegrity)
+	}
+	builder := cyclicBuilder()
+	builder.CondenseCycles()
+	builder.IndexReachability()
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tree.Cycles(), [][]string{{"a", "b"}, {"tool"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+	wantGroups := [][]string{{"tool"}, {"lib"}, {"a", "b"}, {"app"}}
+	if got := tree.ListAscGroups("app"); !reflect.DeepEqual(got, wantGroups) {
+		t.Errorf("ListAscGroups() = %v, want %v", got, wantGroups)
+	}
+	if got, want := tree.ListDescGroups("app"), [][]string{{"app"}, {"b", "a"}, {"lib"}, {"tool"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDescGroups() = %v, want %v", got, want)
+	}
+	if got, want := tree.ListAsc("b"), []string{"lib", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+	if got, want := tree.ListDesc("app"), []string{"app", "b", "a", "lib", "tool"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDesc() = %v, want %v", got, want)
+	}
+	if !tree.DependsOn("a", "a") || !tree.DependsOn("b", "lib") || tree.DependsOn("lib", "a") {
+		t.Errorf("DependsOn() is wrong for the cycles")
+	}
+	if got, want := tree.Subtree("a").Cycles(), [][]string{{"a", "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Subtree().Cycles() = %v, want %v", got, want)
+	}
+}
+
+func TestDepTree_ListAscGroupsCanonical(t *testing.T) {
+	builder := cyclicBuilder()
+	builder.CondenseCycles()
+	builder.SetOrdering(OrderCanonical)
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]string{{"lib"}, {"a", "b"}, {"tool"}, {"app"}}
+	if got := tree.ListAscGroups("app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAscGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestMutableDepTree_CondenseCycles(t *testing.T) {
+	builder := cyclicBuilder()
+	builder.CondenseCycles()
+	tree, err := builder.BuildMutable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tree.RemoveEdge("b", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tree.Cycles(), [][]string{{"tool"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+	if got, want := tree.ListAsc("a"), []string{"lib", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+	if err := tree.AddDeps("b", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tree.AddDeps("lib", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tree.Cycles(), [][]string{{"a", "b", "lib"}, {"tool"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+}
+
+// reviewCycle is a→b, b→a, a→c, top→a built with CondenseCycles.
+func reviewCycle(t *testing.T) *DepTree {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("top", "a")
+	builder.AddDeps("a", "b", "c")
+	builder.AddDeps("b", "a")
+	builder.AddDeps("c")
+	builder.CondenseCycles()
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return tree
+}
+
+func TestDepTree_CriticalPathCondensed(t *testing.T) {
+	tree := reviewCycle(t)
+	want := &CriticalPath{
+		Path: []string{"c", "a", "b", "top"},
+		Cost: 4,
+		Timings: map[string]NodeTiming{
+			"c":   {EarliestStart: 0, EarliestFinish: 1, LatestStart: 0, Slack: 0},
+			"a":   {EarliestStart: 1, EarliestFinish: 3, LatestStart: 1, Slack: 0},
+			"b":   {EarliestStart: 1, EarliestFinish: 3, LatestStart: 1, Slack: 0},
+			"top": {EarliestStart: 3, EarliestFinish: 4, LatestStart: 3, Slack: 0},
+		},
+	}
+	if got := tree.CriticalPath("top"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CriticalPath() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDepTree_ScheduleCondensed(t *testing.T) {
+	tree := reviewCycle(t)
+	got, err := tree.Schedule(2, "top")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &Schedule{
+		Workers:  2,
+		Makespan: 4,
+		Tasks: []Task{
+			{Id: "c", Worker: 0, Start: 0, Finish: 1},
+			{Id: "a", Worker: 0, Start: 1, Finish: 2},
+			{Id: "b", Worker: 0, Start: 2, Finish: 3},
+			{Id: "top", Worker: 0, Start: 3, Finish: 4},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Schedule() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDepTree_TransitiveReductionCondensed(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("top", "a", "c")
+	builder.AddDeps("a", "b", "c")
+	builder.AddDeps("b", "a", "c")
+	builder.AddDeps("c")
+	builder.CondenseCycles()
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reduced := tree.TransitiveReduction()
+	want := map[string][]string{"top": {"a"}, "a": {"b", "c"}, "b": {"a"}, "c": {}}
+	if !reflect.DeepEqual(reduced.deps, want) {
+		t.Errorf("TransitiveReduction() = %v, want %v", reduced.deps, want)
+	}
+	for _, a := range sortedKeys(tree.deps) {
+		for _, b := range sortedKeys(tree.deps) {
+			if tree.DependsOn(a, b) != reduced.DependsOn(a, b) {
+				t.Errorf("DependsOn(%s, %s) = %v after reduction", a, b, reduced.DependsOn(a, b))
+			}
+		}
+	}
+	if got, want := reduced.Cycles(), [][]string{{"a", "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+}
+
+func TestDepTree_OrdersCondensed(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("top", "a", "d")
+	builder.AddDeps("a", "b", "c")
+	builder.AddDeps("b", "a")
+	builder.AddDeps("c")
+	builder.AddDeps("d")
+	builder.CondenseCycles()
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]string{
+		{"c", "a", "b", "d", "top"},
+		{"c", "d", "a", "b", "top"},
+		{"d", "c", "a", "b", "top"},
+	}
+	got := make([][]string, 0)
+	for it := tree.Orders("top"); it.Next(); {
+		got = append(got, it.Order())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Orders() = %v, want %v", got, want)
+	}
+	if count, exact := tree.CountOrders(0, "top"); count != 3 || !exact {
+		t.Errorf("CountOrders() = %v, %v, want %v, %v", count, exact, 3, true)
+	}
+	order := tree.RandomOrder(rand.New(rand.NewSource(1)), "top")
+	if err := tree.Validate(order); err != nil {
+		t.Errorf("Validate(RandomOrder()) error = %v", err)
+	}
+	if err := tree.Validate(tree.ListAsc("top")); err != nil {
+		t.Errorf("Validate(ListAsc()) error = %v", err)
+	}
+	if err := tree.Validate([]string{"c", "b", "a", "d", "top"}); err != nil {
+		t.Errorf("Validate() error = %v, want nil for the cycle listed in another order", err)
+	}
+	var invalid *ValidationError
+	err = tree.Validate([]string{"a", "b", "c", "d", "top"})
+	if !errors.As(err, &invalid) || !reflect.DeepEqual(invalid.Violated, []Edge{{From: "a", To: "c"}}) {
+		t.Errorf("Validate() error = %v, want violated a->c", err)
+	}
+}
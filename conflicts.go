@@ -0,0 +1,147 @@
+package deptree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrConflict = fmt.Errorf("conflict error")
+
+// Conflicter is an optional interface for a Node. If the node implements it, the builders declare the returned ids
+// as the nodes conflicting with the node.
+type Conflicter interface {
+	Conflicts() []string
+}
+
+// ConflictError is returned when two conflicting nodes end up in the same resolved set. NodeTops and OtherTops contain
+// the top nodes which pulled in Node and Other respectively.
+type ConflictError struct {
+	Node      string
+	Other     string
+	NodeTops  []string
+	OtherTops []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: \"%s\" (required by %s) conflicts with \"%s\" (required by %s)", ErrConflict,
+		e.Node, strings.Join(e.NodeTops, ", "), e.Other, strings.Join(e.OtherTops, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// CheckConflicts verifies that the nodes required by the given tops don't conflict with each other. If they do,
+// a ConflictError is returned.
+func (dt *DepTree) CheckConflicts(top ...string) error {
+	if len(dt.conflicts) == 0 {
+		return nil
+	}
+	pulledBy := make(map[string][]string)
+	for _, t := range top {
+		for node := range dt.closure(t) {
+			if !contains(pulledBy[node], t) {
+				pulledBy[node] = append(pulledBy[node], t)
+			}
+		}
+	}
+	for _, node := range sortedKeys(dt.conflicts) {
+		if _, ok := pulledBy[node]; !ok {
+			continue
+		}
+		for _, other := range dt.conflicts[node] {
+			if _, ok := pulledBy[other]; ok && other != node {
+				return &ConflictError{Node: node, Other: other, NodeTops: pulledBy[node], OtherTops: pulledBy[other]}
+			}
+		}
+	}
+	return nil
+}
+
+// selfConflictsCheck verifies that no node requires two conflicting nodes on its own.
+func (dt *DepTree) selfConflictsCheck() error {
+	dependents := make(map[string][]string)
+	for node, deps := range dt.deps {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], node)
+		}
+	}
+	for _, node := range sortedKeys(dt.conflicts) {
+		if _, ok := dt.deps[node]; !ok {
+			continue
+		}
+		nodeAncestors := reachable(dependents, node)
+		for _, other := range dt.conflicts[node] {
+			if _, ok := dt.deps[other]; !ok || other == node {
+				continue
+			}
+			both := make([]string, 0)
+			for ancestor := range reachable(dependents, other) {
+				if nodeAncestors[ancestor] {
+					both = append(both, ancestor)
+				}
+			}
+			if len(both) > 0 {
+				sort.Strings(both)
+				return &ConflictError{Node: node, Other: other, NodeTops: both[:1], OtherTops: both[:1]}
+			}
+		}
+	}
+	return nil
+}
+
+// closure returns the set of nodes required by the top, including the top itself.
+func (dt *DepTree) closure(top string) map[string]bool {
+	if _, ok := dt.deps[top]; !ok {
+		return map[string]bool{}
+	}
+	result := reachable(dt.deps, top)
+	for node := range result {
+		if _, ok := dt.deps[node]; !ok {
+			delete(result, node)
+		}
+	}
+	return result
+}
+
+// reachable returns the set of nodes reachable from the start nodes in the given adjacency map, including the starts.
+func reachable(edges map[string][]string, start ...string) map[string]bool {
+	result := make(map[string]bool, len(start))
+	stack := make([]string, 0, len(start))
+	for _, s := range start {
+		if !result[s] {
+			result[s] = true
+			stack = append(stack, s)
+		}
+	}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range edges[current] {
+			if !result[next] {
+				result[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains[K comparable](list []K, item K) bool {
+	for _, l := range list {
+		if l == item {
+			return true
+		}
+	}
+	return false
+}
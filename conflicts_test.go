@@ -0,0 +1,113 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type conflictingNode struct {
+	testNode
+	conflicts []string
+}
+
+func (cn *conflictingNode) Conflicts() []string {
+	return cn.conflicts
+}
+
+func TestDepTree_CheckConflicts(t *testing.T) {
+	tree := &DepTree{
+		deps: map[string][]string{
+			"app":     {"mysql"},
+			"app2":    {"mariadb"},
+			"app3":    {"common"},
+			"mysql":   {"common"},
+			"mariadb": {"common"},
+			"common":  {},
+		},
+		conflicts: map[string][]string{"mysql": {"mariadb"}},
+	}
+	tests := []struct {
+		name string
+		top  []string
+		want *ConflictError
+	}{
+		{
+			name: "no conflict",
+			top:  []string{"app", "app3"},
+			want: nil,
+		},
+		{
+			name: "conflict between tops",
+			top:  []string{"app", "app2", "app3"},
+			want: &ConflictError{Node: "mysql", Other: "mariadb", NodeTops: []string{"app"}, OtherTops: []string{"app2"}},
+		},
+		{
+			name: "conflict with a top",
+			top:  []string{"mariadb", "app"},
+			want: &ConflictError{Node: "mysql", Other: "mariadb", NodeTops: []string{"app"}, OtherTops: []string{"mariadb"}},
+		},
+		{
+			name: "ignore not existent top",
+			top:  []string{"app", "postgres"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tree.CheckConflicts(tt.top...)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("CheckConflicts() error = %v, want nil", err)
+				}
+				return
+			}
+			var ce *ConflictError
+			if !errors.As(err, &ce) || !errors.Is(err, ErrConflict) {
+				t.Fatalf("CheckConflicts() error = %v, want ConflictError", err)
+			}
+			if !reflect.DeepEqual(ce, tt.want) {
+				t.Errorf("CheckConflicts() = %+v, want %+v", ce, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepTreeBuilder_BuildConflicts(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "mysql", "lib")
+	builder.AddDeps("lib", "mariadb")
+	builder.AddConflicts("mariadb", "mysql")
+	builder.ForceIntegrity()
+	_, err := builder.Build()
+	var ce *ConflictError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Build() error = %v, want ConflictError", err)
+	}
+	want := &ConflictError{Node: "mariadb", Other: "mysql", NodeTops: []string{"app"}, OtherTops: []string{"app"}}
+	if !reflect.DeepEqual(ce, want) {
+		t.Errorf("Build() error = %+v, want %+v", ce, want)
+	}
+}
+
+func TestNDepTree_CheckConflicts(t *testing.T) {
+	nodes := []*conflictingNode{
+		{testNode: testNode{nodeId: "mysql"}, conflicts: []string{"mariadb"}},
+		{testNode: testNode{nodeId: "mariadb"}},
+		{testNode: testNode{nodeId: "app", deps: []string{"mysql"}}},
+	}
+	builder := NewNDepTreeBuilder[*conflictingNode]()
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tree.CheckConflicts(nodes[2]); err != nil {
+		t.Errorf("CheckConflicts() error = %v, want nil", err)
+	}
+	if err := tree.CheckConflicts(nodes[2], nodes[1]); !errors.Is(err, ErrConflict) {
+		t.Errorf("CheckConflicts() error = %v, want %v", err, ErrConflict)
+	}
+}
@@ -0,0 +1,97 @@
+package deptree
+
+// DefaultCost is the cost of a node which doesn't have the cost set.
+const DefaultCost = 1.0
+
+// Coster is an optional interface for a Node. If the node implements it, the builders set the returned value
+// as the cost of the node.
+type Coster interface {
+	Cost() float64
+}
+
+// NodeTiming describes when a node may be executed if the dependencies are executed as early as possible.
+// Slack is the time the node may be delayed without delaying the whole execution.
+type NodeTiming struct {
+	EarliestStart  float64
+	EarliestFinish float64
+	LatestStart    float64
+	Slack          float64
+}
+
+// CriticalPath is the heaviest dependency chain. Path is in ascending order, so the dependency comes before the node.
+// Cost is the total cost of the chain. Timings contain the timing of every node required by the tops.
+type CriticalPath struct {
+	Path    []string
+	Cost    float64
+	Timings map[string]NodeTiming
+}
+
+// CriticalPath returns the heaviest dependency chain of the nodes required by the given tops. The nodes of a cycle
+// condensed by CondenseCycles are treated as a single node with their total cost. They share the timing and they are
+// on the path together.
+func (dt *DepTree) CriticalPath(top ...string) *CriticalPath {
+	order := dt.condensedOrder(top)
+	lookup := dt.condensedLookup()
+	inOrder := make(map[string]bool, len(order))
+	for _, node := range order {
+		inOrder[node] = true
+	}
+	timings := make(map[string]NodeTiming, len(order))
+	dependents := make(map[string][]string)
+	result := &CriticalPath{Path: make([]string, 0), Timings: make(map[string]NodeTiming)}
+	last, found := "", false
+	for _, node := range order {
+		timing := NodeTiming{}
+		deps, _ := lookup(node)
+		for _, dep := range deps {
+			if !inOrder[dep] {
+				continue
+			}
+			dependents[dep] = append(dependents[dep], node)
+			if timings[dep].EarliestFinish > timing.EarliestStart {
+				timing.EarliestStart = timings[dep].EarliestFinish
+			}
+		}
+		timing.EarliestFinish = timing.EarliestStart + dt.groupCost(node)
+		timings[node] = timing
+		if !found || timing.EarliestFinish > result.Cost {
+			result.Cost = timing.EarliestFinish
+			last, found = node, true
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		timing := timings[node]
+		latestFinish := result.Cost
+		for _, dependent := range dependents[node] {
+			if timings[dependent].LatestStart < latestFinish {
+				latestFinish = timings[dependent].LatestStart
+			}
+		}
+		timing.LatestStart = latestFinish - dt.groupCost(node)
+		timing.Slack = timing.LatestStart - timing.EarliestStart
+		timings[node] = timing
+		for _, member := range dt.members(node) {
+			result.Timings[member] = timing
+		}
+	}
+	for found {
+		result.Path = append(append([]string{}, dt.members(last)...), result.Path...)
+		next, hasNext := "", false
+		deps, _ := lookup(last)
+		for _, dep := range deps {
+			if inOrder[dep] && (!hasNext || timings[dep].EarliestFinish > timings[next].EarliestFinish) {
+				next, hasNext = dep, true
+			}
+		}
+		last, found = next, hasNext
+	}
+	return result
+}
+
+func (dt *DepTree) cost(node string) float64 {
+	if c, ok := dt.costs[node]; ok {
+		return c
+	}
+	return DefaultCost
+}
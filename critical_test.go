@@ -0,0 +1,103 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+type costNode struct {
+	testNode
+	cost float64
+}
+
+func (cn *costNode) Cost() float64 {
+	return cn.cost
+}
+
+func TestDepTree_CriticalPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		deps  map[string][]string
+		costs map[string]float64
+		top   []string
+		want  *CriticalPath
+	}{
+		{
+			name:  "weighted diamond",
+			deps:  map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": {}},
+			costs: map[string]float64{"a": 1, "b": 2, "c": 5, "d": 3},
+			top:   []string{"a"},
+			want: &CriticalPath{
+				Path: []string{"d", "c", "a"},
+				Cost: 9,
+				Timings: map[string]NodeTiming{
+					"d": {EarliestStart: 0, EarliestFinish: 3, LatestStart: 0, Slack: 0},
+					"b": {EarliestStart: 3, EarliestFinish: 5, LatestStart: 6, Slack: 3},
+					"c": {EarliestStart: 3, EarliestFinish: 8, LatestStart: 3, Slack: 0},
+					"a": {EarliestStart: 8, EarliestFinish: 9, LatestStart: 8, Slack: 0},
+				},
+			},
+		},
+		{
+			name: "default costs",
+			deps: map[string][]string{"a": {"b"}, "b": {}, "c": {}},
+			top:  []string{"a", "c"},
+			want: &CriticalPath{
+				Path: []string{"b", "a"},
+				Cost: 2,
+				Timings: map[string]NodeTiming{
+					"a": {EarliestStart: 1, EarliestFinish: 2, LatestStart: 1, Slack: 0},
+					"b": {EarliestStart: 0, EarliestFinish: 1, LatestStart: 0, Slack: 0},
+					"c": {EarliestStart: 0, EarliestFinish: 1, LatestStart: 1, Slack: 1},
+				},
+			},
+		},
+		{
+			name: "empty id",
+			deps: map[string][]string{"a": {""}, "": {}},
+			top:  []string{"a"},
+			want: &CriticalPath{
+				Path: []string{"", "a"},
+				Cost: 2,
+				Timings: map[string]NodeTiming{
+					"":  {EarliestStart: 0, EarliestFinish: 1, LatestStart: 0, Slack: 0},
+					"a": {EarliestStart: 1, EarliestFinish: 2, LatestStart: 1, Slack: 0},
+				},
+			},
+		},
+		{
+			name: "empty",
+			deps: map[string][]string{},
+			top:  []string{"a"},
+			want: &CriticalPath{Path: []string{}, Timings: map[string]NodeTiming{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := &DepTree{deps: tt.deps, costs: tt.costs}
+			if got := dt.CriticalPath(tt.top...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CriticalPath() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNDepTree_CriticalPath(t *testing.T) {
+	nodes := []*costNode{
+		{testNode: testNode{nodeId: "app", deps: []string{"db", "cache"}}, cost: 1},
+		{testNode: testNode{nodeId: "db"}, cost: 10},
+		{testNode: testNode{nodeId: "cache"}, cost: 2},
+	}
+	builder := NewNDepTreeBuilder[*costNode]()
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := tree.CriticalPath(nodes[0])
+	if want := []string{"db", "app"}; !reflect.DeepEqual(got.Path, want) || got.Cost != 11 {
+		t.Errorf("CriticalPath() = %v %v, want %v %v", got.Path, got.Cost, want, 11)
+	}
+}
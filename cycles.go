@@ -0,0 +1,201 @@
+package deptree
+
+import "sort"
+
+// MaxCountedCycles is the maximum number of cycles counted by SuggestCycleBreaks in one strongly connected component.
+const MaxCountedCycles = 10000
+
+// CycleBreak is a dependency suggested to be removed to break the cycles. Cycles is the number of the cycles
+// the dependency is part of once the dependencies suggested before it are removed, at most MaxCountedCycles cycles of
+// its strongly connected component are counted.
+type CycleBreak struct {
+	Edge   Edge
+	Cycles int
+}
+
+// SuggestCycleBreaks returns the dependencies to remove to make the builder acyclic, ranked by the number of cycles
+// they are part of. The dependencies are picked greedily, the one in the most cycles first, so the set is small but not
+// necessarily the smallest possible. The cycles are searched only inside the strongly connected components and only
+// the component which lost the dependency is searched again. The result is empty if there are no cycles. Pattern
+// dependencies are expanded, see AddPatternDeps.
+func (dtb *DepTreeBuilder) SuggestCycleBreaks() []CycleBreak {
+	deps, _, err := dtb.expandPatterns()
+	if err != nil {
+		deps = copyDeps(dtb.deps)
+	}
+	components := cyclicComponents(deps)
+	counts := make([]map[Edge]int, len(components))
+	for i, component := range components {
+		counts[i] = cycleCounts(component)
+	}
+	result := make([]CycleBreak, 0)
+	for len(components) > 0 {
+		owner := make(map[Edge]int)
+		edges := make([]Edge, 0)
+		for i := range components {
+			for e := range counts[i] {
+				owner[e] = i
+				edges = append(edges, e)
+			}
+		}
+		sortEdges(edges)
+		best := edges[0]
+		for _, e := range edges[1:] {
+			if counts[owner[e]][e] > counts[owner[best]][best] {
+				best = e
+			}
+		}
+		i := owner[best]
+		result = append(result, CycleBreak{Edge: best, Cycles: counts[i][best]})
+		deps[best.From] = without(deps[best.From], best.To)
+		component := components[i]
+		component[best.From] = without(component[best.From], best.To)
+		components = append(components[:i], components[i+1:]...)
+		counts = append(counts[:i], counts[i+1:]...)
+		for _, split := range cyclicComponents(component) {
+			components = append(components, split)
+			counts = append(counts, cycleCounts(split))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Cycles > result[j].Cycles
+	})
+	return result
+}
+
+// cyclicComponents returns the strongly connected components of the graph which contain a cycle, every component as
+// the graph restricted to its nodes.
+func cyclicComponents(deps map[string][]string) []map[string][]string {
+	result := make([]map[string][]string, 0)
+	for _, nodes := range stronglyConnected(deps) {
+		if len(nodes) == 1 && !contains(deps[nodes[0]], nodes[0]) {
+			continue
+		}
+		in := make(map[string]bool, len(nodes))
+		for _, node := range nodes {
+			in[node] = true
+		}
+		component := make(map[string][]string, len(nodes))
+		for _, node := range nodes {
+			component[node] = make([]string, 0)
+			for _, dep := range deps[node] {
+				if in[dep] {
+					component[node] = append(component[node], dep)
+				}
+			}
+		}
+		result = append(result, component)
+	}
+	return result
+}
+
+// without returns a copy of the list without the item.
+func without(list []string, item string) []string {
+	result := make([]string, 0, len(list))
+	for _, l := range list {
+		if l != item {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+// cycleCounts returns the number of simple cycles each dependency is part of. At most MaxCountedCycles cycles are
+// counted.
+func cycleCounts(deps map[string][]string) map[Edge]int {
+	counts := make(map[Edge]int)
+	for _, cycle := range simpleCycles(deps, MaxCountedCycles) {
+		for i, node := range cycle {
+			counts[Edge{From: node, To: cycle[(i+1)%len(cycle)]}]++
+		}
+	}
+	return counts
+}
+
+// simpleCycles returns at most limit simple cycles of the graph using Johnson's algorithm. A cycle starts with its
+// least node, the dependency of the last node is the first node.
+func simpleCycles(deps map[string][]string, limit int) [][]string {
+	nodes := sortedKeys(deps)
+	result := make([][]string, 0)
+	for i, start := range nodes {
+		if len(result) >= limit {
+			break
+		}
+		allowed := make(map[string]bool, len(nodes)-i)
+		for _, node := range nodes[i:] {
+			allowed[node] = true
+		}
+		component := componentOf(deps, allowed, start)
+		blocked := make(map[string]bool)
+		blockedBy := make(map[string]map[string]bool)
+		var unblock func(node string)
+		unblock = func(node string) {
+			blocked[node] = false
+			for other := range blockedBy[node] {
+				delete(blockedBy[node], other)
+				if blocked[other] {
+					unblock(other)
+				}
+			}
+		}
+		path := make([]string, 0)
+		var circuit func(node string) bool
+		circuit = func(node string) bool {
+			found := false
+			path = append(path, node)
+			blocked[node] = true
+			for _, dep := range deps[node] {
+				if len(result) >= limit {
+					break
+				}
+				switch {
+				case !component[dep]:
+				case dep == start:
+					result = append(result, append([]string{}, path...))
+					found = true
+				case !blocked[dep]:
+					found = circuit(dep) || found
+				}
+			}
+			if found {
+				unblock(node)
+			} else {
+				for _, dep := range deps[node] {
+					if component[dep] {
+						if blockedBy[dep] == nil {
+							blockedBy[dep] = make(map[string]bool)
+						}
+						blockedBy[dep][node] = true
+					}
+				}
+			}
+			path = path[:len(path)-1]
+			return found
+		}
+		circuit(start)
+	}
+	return result
+}
+
+// componentOf returns the strongly connected component of the start node in the graph restricted to the allowed
+// nodes.
+func componentOf(deps map[string][]string, allowed map[string]bool, start string) map[string]bool {
+	restricted := make(map[string][]string, len(allowed))
+	reverse := make(map[string][]string, len(allowed))
+	for node := range allowed {
+		for _, dep := range deps[node] {
+			if allowed[dep] {
+				restricted[node] = append(restricted[node], dep)
+				reverse[dep] = append(reverse[dep], node)
+			}
+		}
+	}
+	forward := reachable(restricted, start)
+	result := make(map[string]bool)
+	for node := range reachable(reverse, start) {
+		if forward[node] {
+			result[node] = true
+		}
+	}
+	return result
+}
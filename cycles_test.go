@@ -0,0 +1,129 @@
+package deptree
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDepTreeBuilder_SuggestCycleBreaks(t *testing.T) {
+	tests := []struct {
+		name string
+		deps map[string][]string
+		want []CycleBreak
+	}{
+		{
+			name: "acyclic",
+			deps: map[string][]string{"a": {"b"}, "b": {}},
+			want: []CycleBreak{},
+		},
+		{
+			name: "shared edge",
+			deps: map[string][]string{"a": {"b"}, "b": {"c", "d"}, "c": {"a"}, "d": {"a"}},
+			want: []CycleBreak{{Edge: Edge{From: "a", To: "b"}, Cycles: 2}},
+		},
+		{
+			name: "self dependency",
+			deps: map[string][]string{"a": {"a", "b"}, "b": {}},
+			want: []CycleBreak{{Edge: Edge{From: "a", To: "a"}, Cycles: 1}},
+		},
+		{
+			name: "independent cycles ranked",
+			deps: map[string][]string{
+				"a": {"b"}, "b": {"a"},
+				"x": {"y"}, "y": {"z", "w"}, "z": {"x"}, "w": {"x"},
+			},
+			want: []CycleBreak{
+				{Edge: Edge{From: "x", To: "y"}, Cycles: 2},
+				{Edge: Edge{From: "a", To: "b"}, Cycles: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewDepTreeBuilder()
+			for node, deps := range tt.deps {
+				builder.AddDeps(node, deps...)
+			}
+			if got := builder.SuggestCycleBreaks(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestCycleBreaks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepTreeBuilder_SuggestCycleBreaksRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for i := 0; i < 50; i++ {
+		builder := NewDepTreeBuilder()
+		for node := 0; node < 8; node++ {
+			builder.AddDeps(string(rune('a' + node)))
+			for dep := 0; dep < 8; dep++ {
+				if rng.Intn(4) == 0 {
+					builder.AddDeps(string(rune('a'+node)), string(rune('a'+dep)))
+				}
+			}
+		}
+		for _, b := range builder.SuggestCycleBreaks() {
+			if b.Cycles < 1 {
+				t.Errorf("SuggestCycleBreaks() suggested %v not in any cycle", b.Edge)
+			}
+			if err := builder.RemoveDep(b.Edge.From, b.Edge.To); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if _, err := builder.Build(); err != nil {
+			t.Errorf("Build() after cycle breaks error = %v", err)
+		}
+	}
+}
+
+func TestDepTreeBuilder_SuggestCycleBreaksTruncated(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	for node := 0; node < 9; node++ {
+		for dep := 0; dep < 9; dep++ {
+			if dep != node {
+				builder.AddDeps(string(rune('a'+node)), string(rune('a'+dep)))
+			}
+		}
+	}
+	builder.AddDeps("z1", "z2")
+	builder.AddDeps("z2", "z1")
+	breaks := builder.SuggestCycleBreaks()
+	found := false
+	for _, b := range breaks {
+		if b.Cycles < 1 {
+			t.Errorf("SuggestCycleBreaks() suggested %v with %d cycles", b.Edge, b.Cycles)
+		}
+		if b.Edge == (Edge{From: "z1", To: "z2"}) {
+			found = b.Cycles == 1
+		}
+	}
+	if !found {
+		t.Errorf("SuggestCycleBreaks() = %v, want z1->z2 in 1 cycle", breaks)
+	}
+}
+
+func TestDepTreeBuilder_SuggestCycleBreaksLargeTree(t *testing.T) {
+	const n = 20000
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("0")
+	for i := 1; i < n; i++ {
+		builder.AddDeps(fmt.Sprint(i), fmt.Sprint(i-1))
+	}
+	builder.AddDeps("10", "11")
+	builder.AddDeps("100", "101")
+	start := time.Now()
+	want := []CycleBreak{
+		{Edge: Edge{From: "10", To: "11"}, Cycles: 1},
+		{Edge: Edge{From: "100", To: "101"}, Cycles: 1},
+	}
+	if got := builder.SuggestCycleBreaks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestCycleBreaks() = %v, want %v", got, want)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SuggestCycleBreaks() took %v on %d nodes, want only the cycles searched", elapsed, n)
+	}
+}
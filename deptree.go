@@ -4,54 +4,97 @@ import "fmt"
 
 var ErrIntegrity = fmt.Errorf("integrity error")
 
-// DepTree is the main dependency manager.
+// DepTree is the main dependency manager. The tree is not modified after it's built, so it's safe for concurrent use.
 type DepTree struct {
-	deps map[string][]string
+	deps       map[string][]string
+	conflicts  map[string][]string
+	costs      map[string]float64
+	typed      map[string][]TypedDep
+	tags       map[string][]string
+	via        map[string]map[string]string
+	priorities map[string]int
+	ordering   Ordering
+	condensed  bool
+	groups     map[string][]string
+	group      map[string]string
+	index      *reachIndex
 }
 
+// ListAsc sorts the dependencies based on provided top nodes. Ascending order means that dependency comes before
+// the node. Many tops may be sorted at once. The function returns the nodes in order merged properly for all tops.
+// The order of the nodes not depending on each other is decided by the ordering, see SetOrdering.
 func (dt *DepTree) ListAsc(top ...string) []string {
-	result := make([]string, 0)
-	for _, t := range top {
-		result = dt.sanitize(append(dt.sanitize(result), dt.listFor(t)...))
-	}
-	return result
+	return dt.order(dt.lookup, top)
 }
 
+// ListDesc sorts the dependencies based on provided top nodes. Descending order means that dependency comes after
+// the node. See ListAsc for more details.
 func (dt *DepTree) ListDesc(top ...string) []string {
-	rs := dt.ListAsc(top...)
-	result := make([]string, len(rs))
-	for i, r := range rs {
-		result[len(rs)-i-1] = r
+	return reversed(dt.ListAsc(top...))
+}
+
+func (dt *DepTree) lookup(node string) ([]string, bool) {
+	deps, ok := dt.deps[node]
+	return deps, ok
+}
+
+func reversed[K any](list []K) []K {
+	result := make([]K, len(list))
+	for i, r := range list {
+		result[len(list)-i-1] = r
 	}
 	return result
 }
 
-func (dt *DepTree) sanitize(deps []string) []string {
-	result := make([]string, 0)
-	test := make(map[string]bool)
-	for _, d := range deps {
-		test[d] = false
-	}
-	l := len(deps) - 1
-	for i := range deps {
-		j := l - i
-		d := deps[j]
-		if test[d] {
-			continue
-		}
-		test[d] = true
-		result = append(result, d)
+// postorder returns the nodes required by the tops in ascending order. The nodes are visited depth first, the last top
+// and the last dependency first, so the nodes required by the later tops and dependencies come earlier. The lookup
+// returns the dependencies of the node and false if the node doesn't exist. Missing nodes are skipped.
+func postorder[K comparable](lookup func(node K) ([]K, bool), top []K) []K {
+	result := make([]K, 0)
+	next := postorderStream(lookup, top)
+	for node, ok := next(); ok; node, ok = next() {
+		result = append(result, node)
 	}
 	return result
 }
 
-func (dt *DepTree) listFor(top string) []string {
-	if _, ok := dt.deps[top]; !ok {
-		return []string{}
+// postorderStream returns a function producing the nodes of postorder one by one. The function returns false when
+// there are no more nodes.
+func postorderStream[K comparable](lookup func(node K) ([]K, bool), top []K) func() (K, bool) {
+	type frame struct {
+		node K
+		deps []K
+	}
+	visited := make(map[K]bool)
+	push := func(stack []frame, node K) []frame {
+		deps, ok := lookup(node)
+		if !ok || visited[node] {
+			return stack
+		}
+		visited[node] = true
+		return append(stack, frame{node: node, deps: deps})
 	}
-	result := []string{top}
-	for _, dep := range dt.deps[top] {
-		result = append(result, dt.listFor(dep)...)
+	stack := make([]frame, 0)
+	i := len(top)
+	return func() (K, bool) {
+		for {
+			for len(stack) > 0 {
+				f := &stack[len(stack)-1]
+				if len(f.deps) == 0 {
+					node := f.node
+					stack = stack[:len(stack)-1]
+					return node, true
+				}
+				dep := f.deps[len(f.deps)-1]
+				f.deps = f.deps[:len(f.deps)-1]
+				stack = push(stack, dep)
+			}
+			if i == 0 {
+				var zero K
+				return zero, false
+			}
+			i--
+			stack = push(stack, top[i])
+		}
 	}
-	return result
 }
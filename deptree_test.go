@@ -1,6 +1,8 @@
 package deptree
 
 import (
+	"fmt"
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -191,3 +193,54 @@ func TestDepTree_List(t *testing.T) {
 		})
 	}
 }
+
+// recursiveListAsc is the original recursive implementation of ListAsc kept as the reference.
+func recursiveListAsc(deps map[string][]string, top ...string) []string {
+	sanitize := func(list []string) []string {
+		result := make([]string, 0)
+		test := make(map[string]bool)
+		for i := len(list) - 1; i >= 0; i-- {
+			if !test[list[i]] {
+				test[list[i]] = true
+				result = append(result, list[i])
+			}
+		}
+		return result
+	}
+	var listFor func(top string) []string
+	listFor = func(top string) []string {
+		if _, ok := deps[top]; !ok {
+			return []string{}
+		}
+		result := []string{top}
+		for _, dep := range deps[top] {
+			result = append(result, listFor(dep)...)
+		}
+		return result
+	}
+	result := make([]string, 0)
+	for _, t := range top {
+		result = sanitize(append(sanitize(result), listFor(t)...))
+	}
+	return result
+}
+
+func TestDepTree_ListAscRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		deps := make(map[string][]string)
+		for i := 0; i < 30; i++ {
+			deps[fmt.Sprint(i)] = make([]string, 0)
+			for j := 0; j < i; j++ {
+				if rng.Intn(8) == 0 {
+					deps[fmt.Sprint(i)] = append(deps[fmt.Sprint(i)], fmt.Sprint(j))
+				}
+			}
+		}
+		top := []string{fmt.Sprint(rng.Intn(30)), fmt.Sprint(rng.Intn(30)), fmt.Sprint(rng.Intn(30)), "x"}
+		dt := &DepTree{deps: deps}
+		if got, want := dt.ListAsc(top...), recursiveListAsc(deps, top...); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ListAsc() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Edge is a dependency of the node From on the node To. Via is the pattern the dependency is expanded from, if any.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Via  string `json:"via,omitempty"`
+}
+
+func (e Edge) String() string {
+	return e.From + arrow(e.Via) + e.To
+}
+
+// Move is a node whose position in the full ascending order changed.
+type Move struct {
+	Id   string `json:"id"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// GraphDiff describes how a dependency tree differs from another one. The full ascending order is the order of ListAsc
+// for all nodes of the tree given in the sorted order. Positions of the moved nodes are indexes in the full ascending
+// order restricted to the nodes existing in both trees, so adding or removing a node doesn't move the others.
+type GraphDiff struct {
+	AddedNodes   []string `json:"addedNodes"`
+	RemovedNodes []string `json:"removedNodes"`
+	AddedEdges   []Edge   `json:"addedEdges"`
+	RemovedEdges []Edge   `json:"removedEdges"`
+	Moved        []Move   `json:"moved"`
+}
+
+// Diff compares the dependency trees. The result describes what changed from a to b.
+func Diff(a, b *DepTree) *GraphDiff {
+	d := &GraphDiff{
+		AddedNodes:   make([]string, 0),
+		RemovedNodes: make([]string, 0),
+		AddedEdges:   make([]Edge, 0),
+		RemovedEdges: make([]Edge, 0),
+		Moved:        make([]Move, 0),
+	}
+	for _, node := range sortedKeys(b.deps) {
+		if _, ok := a.deps[node]; !ok {
+			d.AddedNodes = append(d.AddedNodes, node)
+		}
+	}
+	for _, node := range sortedKeys(a.deps) {
+		if _, ok := b.deps[node]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, node)
+		}
+	}
+	aEdges, bEdges := a.edgeSet(), b.edgeSet()
+	for e := range bEdges {
+		if !aEdges[e] {
+			d.AddedEdges = append(d.AddedEdges, e)
+		}
+	}
+	for e := range aEdges {
+		if !bEdges[e] {
+			d.RemovedEdges = append(d.RemovedEdges, e)
+		}
+	}
+	sortEdges(d.AddedEdges)
+	sortEdges(d.RemovedEdges)
+	aOrder := a.commonOrder(b)
+	aPos := make(map[string]int, len(aOrder))
+	for i, node := range aOrder {
+		aPos[node] = i
+	}
+	for i, node := range b.commonOrder(a) {
+		if aPos[node] != i {
+			d.Moved = append(d.Moved, Move{Id: node, From: aPos[node], To: i})
+		}
+	}
+	return d
+}
+
+// Empty checks whether the trees are the same.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedNodes)+len(d.RemovedNodes)+len(d.AddedEdges)+len(d.RemovedEdges)+len(d.Moved) == 0
+}
+
+// ExitCode returns 0 if the diff is empty and 1 otherwise, like diff(1) does.
+func (d *GraphDiff) ExitCode() int {
+	if d.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// String renders the diff as text, one change per line.
+func (d *GraphDiff) String() string {
+	sb := strings.Builder{}
+	for _, node := range d.AddedNodes {
+		sb.WriteString(fmt.Sprintf("+ node %s\n", node))
+	}
+	for _, node := range d.RemovedNodes {
+		sb.WriteString(fmt.Sprintf("- node %s\n", node))
+	}
+	for _, e := range d.AddedEdges {
+		sb.WriteString(fmt.Sprintf("+ edge %s\n", e))
+	}
+	for _, e := range d.RemovedEdges {
+		sb.WriteString(fmt.Sprintf("- edge %s\n", e))
+	}
+	for _, m := range d.Moved {
+		sb.WriteString(fmt.Sprintf("~ order %s %d->%d\n", m.Id, m.From, m.To))
+	}
+	return sb.String()
+}
+
+// JSON renders the diff as JSON.
+func (d *GraphDiff) JSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// commonOrder returns the full ascending order of the tree restricted to the nodes existing in the other tree.
+func (dt *DepTree) commonOrder(other *DepTree) []string {
+	result := make([]string, 0)
+	for _, node := range dt.ListAsc(sortedKeys(dt.deps)...) {
+		if _, ok := other.deps[node]; ok {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+func (dt *DepTree) edgeSet() map[Edge]bool {
+	result := make(map[Edge]bool)
+	for node, deps := range dt.deps {
+		for _, dep := range deps {
+			result[Edge{From: node, To: dep}] = true
+		}
+	}
+	return result
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+}
@@ -0,0 +1,44 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := &DepTree{deps: map[string][]string{"a": {"b", "c"}, "b": {}, "c": {}, "d": {}}}
+	b := &DepTree{deps: map[string][]string{"a": {"b"}, "b": {}, "c": {"b"}, "e": {"a"}}}
+	want := &GraphDiff{
+		AddedNodes:   []string{"e"},
+		RemovedNodes: []string{"d"},
+		AddedEdges:   []Edge{{From: "c", To: "b"}, {From: "e", To: "a"}},
+		RemovedEdges: []Edge{{From: "a", To: "c"}},
+		Moved:        []Move{{Id: "b", From: 1, To: 0}, {Id: "a", From: 2, To: 1}, {Id: "c", From: 0, To: 2}},
+	}
+	got := Diff(a, b)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() = %+v, want %+v", got, want)
+	}
+	if got.Empty() || got.ExitCode() != 1 {
+		t.Errorf("Empty() = %v, ExitCode() = %d", got.Empty(), got.ExitCode())
+	}
+	wantText := "+ node e\n- node d\n+ edge c->b\n+ edge e->a\n- edge a->c\n" +
+		"~ order b 1->0\n~ order a 2->1\n~ order c 0->2\n"
+	if s := got.String(); s != wantText {
+		t.Errorf("String() = %q, want %q", s, wantText)
+	}
+	wantJSON := `{"addedNodes":["e"],"removedNodes":["d"],"addedEdges":[{"from":"c","to":"b"},{"from":"e","to":"a"}],` +
+		`"removedEdges":[{"from":"a","to":"c"}],"moved":[{"id":"b","from":1,"to":0},{"id":"a","from":2,"to":1},` +
+		`{"id":"c","from":0,"to":2}]}`
+	if j, err := got.JSON(); err != nil || string(j) != wantJSON {
+		t.Errorf("JSON() = %s, %v, want %s", j, err, wantJSON)
+	}
+}
+
+func TestDiff_Empty(t *testing.T) {
+	a := &DepTree{deps: map[string][]string{"a": {"b"}, "b": {}}}
+	got := Diff(a, a)
+	if !got.Empty() || got.ExitCode() != 0 || got.String() != "" {
+		t.Errorf("Diff() = %+v, want empty", got)
+	}
+}
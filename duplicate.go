@@ -0,0 +1,36 @@
+package deptree
+
+import "fmt"
+
+var ErrDuplicateNode = fmt.Errorf("duplicate node")
+
+// DuplicatePolicy decides what happens when a node with an already known id is added.
+type DuplicatePolicy int
+
+const (
+	// DuplicateMergeDeps keeps the first node and adds the dependencies returned by Deps of the duplicate to it.
+	// Everything else the duplicate provides by the optional interfaces, like the cost, the priority, the tags,
+	// the conflicts, the typed and the pattern dependencies, is ignored.
+	DuplicateMergeDeps DuplicatePolicy = iota
+	// DuplicateError rejects the duplicate with a DuplicateNodeError.
+	DuplicateError
+	// DuplicateKeepFirst keeps the first node and ignores the duplicate.
+	DuplicateKeepFirst
+	// DuplicateReplace replaces the first node with the duplicate.
+	DuplicateReplace
+)
+
+// DuplicateNodeError is returned when a node with an already known id is added and the policy is DuplicateError.
+type DuplicateNodeError struct {
+	Id        string
+	Existing  Node
+	Duplicate Node
+}
+
+func (e *DuplicateNodeError) Error() string {
+	return fmt.Sprintf("%s: \"%s\" is already added as %v, duplicate %v", ErrDuplicateNode, e.Id, e.Existing, e.Duplicate)
+}
+
+func (e *DuplicateNodeError) Unwrap() error {
+	return ErrDuplicateNode
+}
@@ -0,0 +1,98 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNDepTreeBuilder_AddNodeDuplicate(t *testing.T) {
+	first := &testNode{nodeId: "a", deps: []string{"b"}}
+	second := &testNode{nodeId: "a", deps: []string{"c"}}
+	tests := []struct {
+		name     string
+		policy   DuplicatePolicy
+		wantNode *testNode
+		wantDeps []string
+		wantErr  bool
+	}{
+		{name: "merge deps", policy: DuplicateMergeDeps, wantNode: first, wantDeps: []string{"b", "c"}},
+		{name: "keep first", policy: DuplicateKeepFirst, wantNode: first, wantDeps: []string{"b"}},
+		{name: "replace", policy: DuplicateReplace, wantNode: second, wantDeps: []string{"c"}},
+		{name: "error", policy: DuplicateError, wantNode: first, wantDeps: []string{"b"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewNDepTreeBuilder[*testNode]()
+			builder.SetDuplicatePolicy(tt.policy)
+			if err := builder.TryAddNode(first); err != nil {
+				t.Fatalf("TryAddNode() error = %v", err)
+			}
+			err := builder.TryAddNode(second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TryAddNode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var de *DuplicateNodeError
+				if !errors.As(err, &de) || !errors.Is(err, ErrDuplicateNode) {
+					t.Fatalf("TryAddNode() error = %v, want DuplicateNodeError", err)
+				}
+				if de.Existing != Node(first) || de.Duplicate != Node(second) {
+					t.Errorf("TryAddNode() error = %+v, want both nodes", de)
+				}
+			}
+			if builder.nodes["a"] != tt.wantNode {
+				t.Errorf("AddNode() node = %v, want %v", builder.nodes["a"], tt.wantNode)
+			}
+			if !reflect.DeepEqual(builder.builder.deps["a"], tt.wantDeps) {
+				t.Errorf("AddNode() deps = %v, want %v", builder.builder.deps["a"], tt.wantDeps)
+			}
+		})
+	}
+}
+
+func TestIDepTreeBuilder_AddNodeDuplicate(t *testing.T) {
+	builder := NewIDepTreeBuilder()
+	builder.SetDuplicatePolicy(DuplicateError)
+	if err := builder.TryAddNode(&testNode{nodeId: "a"}); err != nil {
+		t.Fatalf("TryAddNode() error = %v", err)
+	}
+	if err := builder.TryAddNode(&conflictingNode{testNode: testNode{nodeId: "a"}}); !errors.Is(err, ErrDuplicateNode) {
+		t.Errorf("TryAddNode() error = %v, want %v", err, ErrDuplicateNode)
+	}
+}
+
+func TestNDepTreeBuilder_AddNodeMergeDepsKeepsMetadata(t *testing.T) {
+	first := &costNode{testNode: testNode{nodeId: "a", deps: []string{"b"}}, cost: 5}
+	second := &costNode{testNode: testNode{nodeId: "a", deps: []string{"c"}}, cost: 9}
+	builder := NewNDepTreeBuilder[*costNode]()
+	builder.AddNode(first)
+	builder.AddNode(second)
+	builder.AddNode(&costNode{testNode: testNode{nodeId: "b"}, cost: 1})
+	builder.AddNode(&costNode{testNode: testNode{nodeId: "c"}, cost: 1})
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tree.tree.cost("a"); got != 5 {
+		t.Errorf("cost() = %v, want %v", got, 5.0)
+	}
+	if got, want := tree.tree.deps["a"], []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deps = %v, want %v", got, want)
+	}
+}
+
+func TestNDepTreeBuilder_AddNodeDuplicateErrorOnBuild(t *testing.T) {
+	builder := NewNDepTreeBuilder[*testNode]()
+	builder.SetDuplicatePolicy(DuplicateError)
+	var add func(*testNode) = builder.AddNode
+	add(&testNode{nodeId: "a"})
+	add(&testNode{nodeId: "a", deps: []string{"b"}})
+	var de *DuplicateNodeError
+	if _, err := builder.Build(); !errors.As(err, &de) || de.Id != "a" {
+		t.Errorf("Build() error = %v, want DuplicateNodeError", err)
+	}
+	if !reflect.DeepEqual(builder.builder.deps["a"], []string{}) {
+		t.Errorf("AddNode() deps = %v, want the duplicate ignored", builder.builder.deps["a"])
+	}
+}
@@ -15,11 +15,42 @@ func NewIDepTreeBuilder() *IDepTreeBuilder {
 	})
 }
 
-// AddNode adds a node to the IDepTreeBuilder.
+// AddNode adds a node to the IDepTreeBuilder. See NDepTreeBuilder.AddNode for more details.
 func (dtb *IDepTreeBuilder) AddNode(node Node) {
 	(*NDepTreeBuilder[Node])(dtb).AddNode(node)
 }
 
+// TryAddNode adds a node to the IDepTreeBuilder. See NDepTreeBuilder.TryAddNode for more details.
+func (dtb *IDepTreeBuilder) TryAddNode(node Node) error {
+	return (*NDepTreeBuilder[Node])(dtb).TryAddNode(node)
+}
+
+// SetDuplicatePolicy sets the policy used by AddNode when a node with the same id is added again.
+// The default policy is DuplicateMergeDeps.
+func (dtb *IDepTreeBuilder) SetDuplicatePolicy(policy DuplicatePolicy) {
+	(*NDepTreeBuilder[Node])(dtb).SetDuplicatePolicy(policy)
+}
+
+// AllowEmptyPatterns makes the pattern dependencies matching no nodes allowed. See DepTreeBuilder.AllowEmptyPatterns.
+func (dtb *IDepTreeBuilder) AllowEmptyPatterns() {
+	(*NDepTreeBuilder[Node])(dtb).AllowEmptyPatterns()
+}
+
+// RemoveNode removes the node with the given id. See NDepTreeBuilder.RemoveNode for more details.
+func (dtb *IDepTreeBuilder) RemoveNode(id string, mode RemoveMode) error {
+	return (*NDepTreeBuilder[Node])(dtb).RemoveNode(id, mode)
+}
+
+// RemoveDep removes the dependency of the node with the given id. The node object is not modified.
+func (dtb *IDepTreeBuilder) RemoveDep(id, dep string) error {
+	return (*NDepTreeBuilder[Node])(dtb).RemoveDep(id, dep)
+}
+
+// ReplaceDeps replaces all dependencies of the node with the given id. See NDepTreeBuilder.ReplaceDeps for more details.
+func (dtb *IDepTreeBuilder) ReplaceDeps(id string, deps ...string) error {
+	return (*NDepTreeBuilder[Node])(dtb).ReplaceDeps(id, deps...)
+}
+
 // Build builds a dependency tree from the IDepTreeBuilder. If node for a dependency is not added
 func (dtb *IDepTreeBuilder) Build() (*IDepTree, error) {
 	t, err := (*NDepTreeBuilder[Node])(dtb).Build()
@@ -53,3 +84,35 @@ func (dt *IDepTree) ListDesc(top ...Node) []Node {
 func (dt *IDepTree) ListDescStr(top ...string) []Node {
 	return (*NDepTree[Node])(dt).ListDescStr(top...)
 }
+
+// CheckConflicts verifies that the nodes required by the given tops don't conflict with each other.
+// See DepTree.CheckConflicts for more details.
+func (dt *IDepTree) CheckConflicts(top ...Node) error {
+	return (*NDepTree[Node])(dt).CheckConflicts(top...)
+}
+
+// CheckConflictsStr takes strings representing node ids. See CheckConflicts for more details.
+func (dt *IDepTree) CheckConflictsStr(top ...string) error {
+	return (*NDepTree[Node])(dt).CheckConflictsStr(top...)
+}
+
+// CriticalPath returns the heaviest dependency chain of the nodes required by the given tops.
+// See DepTree.CriticalPath for more details.
+func (dt *IDepTree) CriticalPath(top ...Node) *CriticalPath {
+	return (*NDepTree[Node])(dt).CriticalPath(top...)
+}
+
+// CriticalPathStr takes strings representing node ids. See CriticalPath for more details.
+func (dt *IDepTree) CriticalPathStr(top ...string) *CriticalPath {
+	return (*NDepTree[Node])(dt).CriticalPathStr(top...)
+}
+
+// Schedule assigns the nodes required by the given tops to the workers. See DepTree.Schedule for more details.
+func (dt *IDepTree) Schedule(workers int, top ...Node) (*Schedule, error) {
+	return (*NDepTree[Node])(dt).Schedule(workers, top...)
+}
+
+// ScheduleStr takes strings representing node ids. See Schedule for more details.
+func (dt *IDepTree) ScheduleStr(workers int, top ...string) (*Schedule, error) {
+	return (*NDepTree[Node])(dt).ScheduleStr(workers, top...)
+}
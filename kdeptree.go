@@ -0,0 +1,137 @@
+package deptree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeK is an interface for any object that can be used as a node in a dependency tree with ids of any comparable type,
+// like integers, arrays or small structs.
+type NodeK[K comparable] interface {
+	NodeId() K
+	Deps() []K
+}
+
+// KDepTreeBuilder collects nodes with ids of the type K needed to build a dependency tree. It shares the sorting and
+// the integrity and cycle checks with DepTree, the ids are kept as they are, so the errors show them as printed by fmt.
+// It covers only building, listing and DependsOn. Everything else of DepTreeBuilder and DepTree, like the duplicate
+// policy, ForceIntegrity, the orderings, the optional interfaces of Node, conflicts, costs, Subtree and Validate,
+// is available only for the string ids.
+type KDepTreeBuilder[K comparable, N NodeK[K]] struct {
+	keys  []K
+	deps  map[K][]K
+	nodes map[K]N
+}
+
+// NewKDepTreeBuilder returns a new KDepTreeBuilder.
+func NewKDepTreeBuilder[K comparable, N NodeK[K]]() *KDepTreeBuilder[K, N] {
+	return &KDepTreeBuilder[K, N]{
+		keys:  make([]K, 0),
+		deps:  make(map[K][]K),
+		nodes: make(map[K]N),
+	}
+}
+
+// AddNode adds a node to the KDepTreeBuilder. If a node with the same id is already added, the first node is kept and
+// the dependencies of the duplicate are added to it.
+func (dtb *KDepTreeBuilder[K, N]) AddNode(node N) {
+	id := node.NodeId()
+	if _, ok := dtb.nodes[id]; !ok {
+		dtb.keys = append(dtb.keys, id)
+		dtb.nodes[id] = node
+	}
+	if _, ok := dtb.deps[id]; !ok {
+		dtb.deps[id] = make([]K, 0)
+	}
+	for _, dep := range node.Deps() {
+		if !contains(dtb.deps[id], dep) {
+			dtb.deps[id] = append(dtb.deps[id], dep)
+		}
+	}
+}
+
+// Build builds a dependency tree from the KDepTreeBuilder. If node for a dependency is not added to the builder
+// an integrity error will be returned. The nodes are checked for cycles in the order they were added.
+func (dtb *KDepTreeBuilder[K, N]) Build() (*KDepTree[K, N], error) {
+	if err := integrityCheck(dtb.deps); err != nil {
+		return nil, err
+	}
+	if ch := findCycle(dtb.deps, dtb.keys); ch != nil {
+		return nil, fmt.Errorf("%w: cycle detected: %s", ErrIntegrity, keyChain(ch))
+	}
+	result := &KDepTree[K, N]{
+		deps:  make(map[K][]K, len(dtb.deps)),
+		nodes: make(map[K]N, len(dtb.nodes)),
+	}
+	for id, deps := range dtb.deps {
+		result.deps[id] = append([]K{}, deps...)
+	}
+	for id, node := range dtb.nodes {
+		result.nodes[id] = node
+	}
+	return result, nil
+}
+
+// keyChain formats the chain of ids like chainString.
+func keyChain[K comparable](chain []K) string {
+	parts := make([]string, len(chain))
+	for i, k := range chain {
+		parts[i] = fmt.Sprint(k)
+	}
+	return strings.Join(parts, arrow(""))
+}
+
+// KDepTree is an object sorting dependencies with ids of the type K to the lists. It supports only the listing and
+// DependsOn, see KDepTreeBuilder. It's safe for concurrent use.
+type KDepTree[K comparable, N NodeK[K]] struct {
+	deps  map[K][]K
+	nodes map[K]N
+}
+
+// ListAsc sorts the dependencies based on provided top nodes. See NDepTree.ListAsc for more details.
+func (dt *KDepTree[K, N]) ListAsc(top ...N) []N {
+	return dt.ListAscId(dt.ids(top)...)
+}
+
+// ListAscId takes ids of the nodes. See ListAsc for more details.
+func (dt *KDepTree[K, N]) ListAscId(top ...K) []N {
+	return dt.list(postorder(dt.lookup, top))
+}
+
+// ListDesc sorts the dependencies based on provided top nodes. See NDepTree.ListDesc for more details.
+func (dt *KDepTree[K, N]) ListDesc(top ...N) []N {
+	return dt.ListDescId(dt.ids(top)...)
+}
+
+// ListDescId takes ids of the nodes. See ListDesc for more details.
+func (dt *KDepTree[K, N]) ListDescId(top ...K) []N {
+	return dt.list(reversed(postorder(dt.lookup, top)))
+}
+
+// DependsOn checks whether node a depends on node b, directly or transitively. See DepTree.DependsOn for more details.
+func (dt *KDepTree[K, N]) DependsOn(a, b K) bool {
+	_, okA := dt.deps[a]
+	_, okB := dt.deps[b]
+	return okA && okB && reaches(dt.deps, a, b)
+}
+
+func (dt *KDepTree[K, N]) lookup(node K) ([]K, bool) {
+	deps, ok := dt.deps[node]
+	return deps, ok
+}
+
+func (dt *KDepTree[K, N]) list(ids []K) []N {
+	result := make([]N, len(ids))
+	for i, id := range ids {
+		result[i] = dt.nodes[id]
+	}
+	return result
+}
+
+func (dt *KDepTree[K, N]) ids(nodes []N) []K {
+	ids := make([]K, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.NodeId()
+	}
+	return ids
+}
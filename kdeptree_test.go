@@ -0,0 +1,112 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type point struct {
+	x, y int
+}
+
+type pointNode struct {
+	id   point
+	deps []point
+}
+
+func (pn *pointNode) NodeId() point {
+	return pn.id
+}
+
+func (pn *pointNode) Deps() []point {
+	return pn.deps
+}
+
+func TestKDepTree_List(t *testing.T) {
+	nodes := []*pointNode{
+		{id: point{0, 0}, deps: []point{{1, 0}, {0, 1}}},
+		{id: point{1, 0}, deps: []point{{1, 1}}},
+		{id: point{0, 1}, deps: []point{{1, 1}}},
+		{id: point{1, 1}},
+	}
+	builder := NewKDepTreeBuilder[point, *pointNode]()
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*pointNode{nodes[3], nodes[2], nodes[1], nodes[0]}
+	if got := tree.ListAsc(nodes[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+	want = []*pointNode{nodes[1], nodes[3]}
+	if got := tree.ListDescId(point{1, 0}, point{9, 9}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDescId() = %v, want %v", got, want)
+	}
+	if !tree.DependsOn(point{0, 0}, point{1, 1}) || tree.DependsOn(point{1, 1}, point{0, 0}) {
+		t.Errorf("DependsOn() returned wrong result")
+	}
+}
+
+type intNode struct {
+	id   int
+	deps []int
+}
+
+func (in *intNode) NodeId() int {
+	return in.id
+}
+
+func (in *intNode) Deps() []int {
+	return in.deps
+}
+
+func TestKDepTreeBuilder_Build(t *testing.T) {
+	builder := NewKDepTreeBuilder[int, *intNode]()
+	builder.AddNode(&intNode{id: 1, deps: []int{2}})
+	if _, err := builder.Build(); !errors.Is(err, ErrIntegrity) || err.Error() != `integrity error: missing dependency "2"` {
+		t.Errorf("Build() error = %v, want %v", err, ErrIntegrity)
+	}
+	builder.AddNode(&intNode{id: 2, deps: []int{1}})
+	if _, err := builder.Build(); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("Build() error = %v, want %v", err, ErrIntegrity)
+	}
+}
+
+type anyNode struct {
+	id   any
+	deps []any
+}
+
+func (an *anyNode) NodeId() any {
+	return an.id
+}
+
+func (an *anyNode) Deps() []any {
+	return an.deps
+}
+
+func TestKDepTreeBuilder_BuildKeysPrintedTheSame(t *testing.T) {
+	builder := NewKDepTreeBuilder[any, *anyNode]()
+	nodes := []*anyNode{{id: 1, deps: []any{"1"}}, {id: "1"}, {id: 2, deps: []any{1}}}
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tree.ListAscId(2), []*anyNode{nodes[1], nodes[0], nodes[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAscId() = %v, want %v", got, want)
+	}
+	if !tree.DependsOn(2, "1") || tree.DependsOn("1", 1) {
+		t.Errorf("DependsOn() returned wrong result")
+	}
+	builder.AddNode(&anyNode{id: "1", deps: []any{2}})
+	if _, err := builder.Build(); !errors.Is(err, ErrIntegrity) || err.Error() != "integrity error: cycle detected: 1->1->2->1" {
+		t.Errorf("Build() error = %v, want %v", err, ErrIntegrity)
+	}
+}
@@ -0,0 +1,151 @@
+package deptree
+
+// TypedDep is a dependency with a kind, like "build", "runtime" or "test", and arbitrary metadata.
+type TypedDep struct {
+	Id   string
+	Kind string
+	Meta map[string]any
+}
+
+// TypedDeper is an optional interface for a Node. If the node implements it, the builders add the returned
+// dependencies as typed dependencies of the node.
+type TypedDeper interface {
+	TypedDeps() []TypedDep
+}
+
+// AddTypedDeps adds dependencies of the given kinds to the node. A dependency may be added with more kinds, adding
+// the same kind again replaces the metadata. A dependency without any kind is untyped, see ListAscKinds.
+func (dtb *DepTreeBuilder) AddTypedDeps(node string, deps ...TypedDep) {
+	ids := make([]string, len(deps))
+	for i, dep := range deps {
+		ids[i] = dep.Id
+	}
+	dtb.AddDeps(node, ids...)
+	if dtb.typed == nil {
+		dtb.typed = make(map[string][]TypedDep)
+	}
+	for _, dep := range deps {
+		dtb.typed[node] = withTypedDep(dtb.typed[node], dep)
+	}
+}
+
+// TypedDeps returns the dependencies of the node with their kinds and metadata in the order of the dependencies.
+// An untyped dependency is returned once with an empty kind.
+func (dt *DepTree) TypedDeps(node string) []TypedDep {
+	result := make([]TypedDep, 0)
+	for _, dep := range dt.deps[node] {
+		found := false
+		for _, td := range dt.typed[node] {
+			if td.Id == dep {
+				result = append(result, td)
+				found = true
+			}
+		}
+		if !found {
+			result = append(result, TypedDep{Id: dep})
+		}
+	}
+	return result
+}
+
+// ListAscKinds works like ListAsc, but follows only the dependencies of the given kinds. Untyped dependencies are
+// always followed.
+func (dt *DepTree) ListAscKinds(kinds []string, top ...string) []string {
+	return dt.order(dt.kindLookup(kinds), top)
+}
+
+// ListDescKinds works like ListDesc, but follows only the dependencies of the given kinds. See ListAscKinds for more
+// details.
+func (dt *DepTree) ListDescKinds(kinds []string, top ...string) []string {
+	return reversed(dt.ListAscKinds(kinds, top...))
+}
+
+func (dt *DepTree) kindLookup(kinds []string) func(string) ([]string, bool) {
+	return func(node string) ([]string, bool) {
+		deps, ok := dt.deps[node]
+		if !ok || len(dt.typed[node]) == 0 {
+			return deps, ok
+		}
+		result := make([]string, 0, len(deps))
+		for _, dep := range deps {
+			typed, selected := false, false
+			for _, td := range dt.typed[node] {
+				if td.Id == dep {
+					typed = true
+					selected = selected || contains(kinds, td.Kind)
+				}
+			}
+			if !typed || selected {
+				result = append(result, dep)
+			}
+		}
+		return result, true
+	}
+}
+
+// withTypedDep returns the typed dependencies with the dependency added or replaced if the same kind exists.
+func withTypedDep(deps []TypedDep, dep TypedDep) []TypedDep {
+	for i, td := range deps {
+		if td.Id == dep.Id && td.Kind == dep.Kind {
+			result := append([]TypedDep{}, deps...)
+			result[i] = dep
+			return result
+		}
+	}
+	return append(deps, dep)
+}
+
+// withoutTypedDeps returns the typed dependencies without the dependencies missing in the ids.
+func withoutTypedDeps(deps []TypedDep, ids []string) []TypedDep {
+	result := make([]TypedDep, 0, len(deps))
+	for _, td := range deps {
+		if contains(ids, td.Id) {
+			result = append(result, td)
+		}
+	}
+	return result
+}
+
+// ListAscKinds works like ListAsc, but follows only the dependencies of the given kinds.
+// See DepTree.ListAscKinds for more details.
+func (dt *NDepTree[N]) ListAscKinds(kinds []string, top ...N) []N {
+	return dt.ListAscKindsStr(kinds, dt.stringify(top)...)
+}
+
+// ListAscKindsStr takes strings representing node ids. See ListAscKinds for more details.
+func (dt *NDepTree[N]) ListAscKindsStr(kinds []string, top ...string) []N {
+	return dt.list(dt.tree.ListAscKinds(kinds, top...))
+}
+
+// ListDescKinds works like ListDesc, but follows only the dependencies of the given kinds.
+// See DepTree.ListAscKinds for more details.
+func (dt *NDepTree[N]) ListDescKinds(kinds []string, top ...N) []N {
+	return dt.ListDescKindsStr(kinds, dt.stringify(top)...)
+}
+
+// ListDescKindsStr takes strings representing node ids. See ListDescKinds for more details.
+func (dt *NDepTree[N]) ListDescKindsStr(kinds []string, top ...string) []N {
+	return dt.list(dt.tree.ListDescKinds(kinds, top...))
+}
+
+// ListAscKinds works like ListAsc, but follows only the dependencies of the given kinds.
+// See DepTree.ListAscKinds for more details.
+func (dt *IDepTree) ListAscKinds(kinds []string, top ...Node) []Node {
+	return (*NDepTree[Node])(dt).ListAscKinds(kinds, top...)
+}
+
+// ListAscKindsStr takes strings representing node ids. See ListAscKinds for more details.
+func (dt *IDepTree) ListAscKindsStr(kinds []string, top ...string) []Node {
+	return (*NDepTree[Node])(dt).ListAscKindsStr(kinds, top...)
+}
+
+// ListDescKinds works like ListDesc, but follows only the dependencies of the given kinds.
+// See DepTree.ListAscKinds for more details.
+func (dt *IDepTree) ListDescKinds(kinds []string, top ...Node) []Node {
+	return (*NDepTree[Node])(dt).ListDescKinds(kinds, top...)
+}
+
+// ListDescKindsStr takes strings representing node ids. See ListDescKinds for more details.
+func (dt *IDepTree) ListDescKindsStr(kinds []string, top ...string) []Node {
+	return (*NDepTree[Node])(dt).ListDescKindsStr(kinds, top...)
+}
@@ -0,0 +1,107 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+type typedNode struct {
+	testNode
+	typed []TypedDep
+}
+
+func (tn *typedNode) TypedDeps() []TypedDep {
+	return tn.typed
+}
+
+func newKindsBuilder() *DepTreeBuilder {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "config")
+	builder.AddTypedDeps("app",
+		TypedDep{Id: "compiler", Kind: "build"},
+		TypedDep{Id: "db", Kind: "runtime", Meta: map[string]any{"port": 5432}},
+		TypedDep{Id: "db", Kind: "test"},
+		TypedDep{Id: "mock", Kind: "test"},
+	)
+	builder.AddTypedDeps("db", TypedDep{Id: "disk", Kind: "runtime"})
+	builder.ForceIntegrity()
+	return builder
+}
+
+func TestDepTree_ListAscKinds(t *testing.T) {
+	tree, err := newKindsBuilder().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name  string
+		kinds []string
+		want  []string
+	}{
+		{name: "build", kinds: []string{"build"}, want: []string{"compiler", "config", "app"}},
+		{name: "runtime", kinds: []string{"runtime"}, want: []string{"disk", "db", "config", "app"}},
+		{name: "test", kinds: []string{"test"}, want: []string{"mock", "db", "config", "app"}},
+		{name: "none", kinds: nil, want: []string{"config", "app"}},
+		{
+			name:  "all",
+			kinds: []string{"build", "runtime", "test"},
+			want:  []string{"mock", "disk", "db", "compiler", "config", "app"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tree.ListAscKinds(tt.kinds, "app"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAscKinds() = %v, want %v", got, tt.want)
+			}
+			if got := tree.ListDescKinds(tt.kinds, "app"); !reflect.DeepEqual(got, reversed(tt.want)) {
+				t.Errorf("ListDescKinds() = %v, want %v", got, reversed(tt.want))
+			}
+		})
+	}
+	if got, want := tree.ListAsc("app"), tree.ListAscKinds([]string{"build", "runtime", "test"}, "app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+}
+
+func TestDepTree_TypedDeps(t *testing.T) {
+	builder := newKindsBuilder()
+	if err := builder.RemoveDep("app", "mock"); err != nil {
+		t.Fatalf("RemoveDep() error = %v", err)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []TypedDep{
+		{Id: "config"},
+		{Id: "compiler", Kind: "build"},
+		{Id: "db", Kind: "runtime", Meta: map[string]any{"port": 5432}},
+		{Id: "db", Kind: "test"},
+	}
+	if got := tree.TypedDeps("app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("TypedDeps() = %v, want %v", got, want)
+	}
+	sub := tree.Subtree("db")
+	if got, want := sub.TypedDeps("db"), []TypedDep{{Id: "disk", Kind: "runtime"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TypedDeps() = %v, want %v", got, want)
+	}
+}
+
+func TestNDepTree_ListAscKinds(t *testing.T) {
+	nodes := []*typedNode{
+		{testNode: testNode{nodeId: "app"}, typed: []TypedDep{{Id: "lib", Kind: "build"}, {Id: "db", Kind: "runtime"}}},
+		{testNode: testNode{nodeId: "lib"}},
+		{testNode: testNode{nodeId: "db"}},
+	}
+	builder := NewNDepTreeBuilder[*typedNode]()
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := tree.ListDescKinds([]string{"runtime"}, nodes[0]), []*typedNode{nodes[0], nodes[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDescKinds() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package deptree
+
+import "fmt"
+
+// MergeOptions configures merging of the builders.
+type MergeOptions struct {
+	// Namespaces are the prefixes of the node ids, one for every builder in the order of the builders. A node id of
+	// the builder becomes the namespace, the separator and the id. Dependencies on the nodes of the same builder are
+	// renamed too, other dependencies are kept, so they may refer to the nodes of the other builders. An empty or
+	// missing namespace leaves the ids of the builder unchanged.
+	Namespaces []string
+	// Separator joins the namespace and the node id. It's "/" if empty.
+	Separator string
+	// Policy decides what happens when a node id is defined by more than one builder. DuplicateError makes the merge
+	// fail with ErrDuplicateNode. Like in NDepTreeBuilder, DuplicateMergeDeps adds only the dependencies of the later
+	// definitions, their costs, priorities, tags, conflicts, typed and pattern dependencies are ignored.
+	Policy DuplicatePolicy
+}
+
+// Merge merges the builders into a new builder with the default options. The dependencies of the nodes defined
+// by more than one builder are merged, the rest is taken from the first definition. Integrity and cycles are checked across all builders when the result is built.
+func Merge(builders ...*DepTreeBuilder) (*DepTreeBuilder, error) {
+	return MergeWith(MergeOptions{}, builders...)
+}
+
+// MergeWith merges the builders into a new builder with the given options. The builders are not modified.
+// Pattern dependencies are not renamed, they are matched against the ids of the result when it's built.
+func MergeWith(opts MergeOptions, builders ...*DepTreeBuilder) (*DepTreeBuilder, error) {
+	result := NewDepTreeBuilder()
+	sources := make(map[string]int)
+	for i, b := range builders {
+		rename := opts.renamer(i, b.deps)
+		result.indexed = result.indexed || b.indexed
+		result.allowEmptyPatterns = result.allowEmptyPatterns || b.allowEmptyPatterns
+		result.condensing = result.condensing || b.condensing
+		if result.ordering == OrderDepthFirst {
+			result.ordering = b.ordering
+		}
+		for _, node := range sortedKeys(b.deps) {
+			id := rename(node)
+			deps := make([]string, len(b.deps[node]))
+			for j, dep := range b.deps[node] {
+				deps[j] = rename(dep)
+			}
+			if source, ok := sources[id]; ok {
+				switch opts.Policy {
+				case DuplicateError:
+					return nil, fmt.Errorf("%w: \"%s\" is defined by builders %d and %d", ErrDuplicateNode, id, source, i)
+				case DuplicateKeepFirst:
+					continue
+				case DuplicateReplace:
+					result.ReplaceDeps(id, deps...)
+					result.dropMetadata(id)
+				default:
+					result.AddDeps(id, deps...)
+					continue
+				}
+			} else {
+				result.AddDeps(id, deps...)
+			}
+			sources[id] = i
+			for _, c := range b.conflicts[node] {
+				result.AddConflicts(id, rename(c))
+			}
+			if cost, ok := b.costs[node]; ok {
+				result.SetCost(id, cost)
+			}
+			if priority, ok := b.priorities[node]; ok {
+				result.SetPriority(id, priority)
+			}
+			result.AddTags(id, b.tags[node]...)
+			if patterns, ok := b.patterns[node]; ok {
+				result.AddPatternDeps(id, patterns...)
+			}
+			for _, td := range b.typed[node] {
+				td.Id = rename(td.Id)
+				result.AddTypedDeps(id, td)
+			}
+		}
+	}
+	return result, nil
+}
+
+// MergeN merges the builders into a new builder with the default options. See Merge for more details.
+func MergeN[N Node](builders ...*NDepTreeBuilder[N]) (*NDepTreeBuilder[N], error) {
+	return MergeNWith(MergeOptions{}, builders...)
+}
+
+// MergeNWith merges the builders into a new builder with the given options. See MergeWith for more details.
+// The node objects are not renamed, so with namespaces the nodes should be listed by the Str methods of the tree.
+// DuplicateError makes the merge fail with a DuplicateNodeError. The result keeps the first duplicate error the builders
+// deferred to Build and uses the duplicate policy of the first builder which changed it.
+func MergeNWith[N Node](opts MergeOptions, builders ...*NDepTreeBuilder[N]) (*NDepTreeBuilder[N], error) {
+	result := &NDepTreeBuilder[N]{nodes: make(map[string]N)}
+	nodes := result.nodes
+	inner := make([]*DepTreeBuilder, len(builders))
+	for i, b := range builders {
+		inner[i] = b.builder
+		if result.err == nil {
+			result.err = b.err
+		}
+		if result.policy == DuplicateMergeDeps {
+			result.policy = b.policy
+		}
+		rename := opts.renamer(i, b.builder.deps)
+		for _, id := range sortedKeys(b.nodes) {
+			node := b.nodes[id]
+			existing, ok := nodes[rename(id)]
+			switch {
+			case ok && opts.Policy == DuplicateError:
+				return nil, &DuplicateNodeError{Id: rename(id), Existing: existing, Duplicate: node}
+			case ok && opts.Policy != DuplicateReplace:
+				continue
+			}
+			nodes[rename(id)] = node
+		}
+	}
+	builder, err := MergeWith(opts, inner...)
+	if err != nil {
+		return nil, err
+	}
+	result.builder = builder
+	return result, nil
+}
+
+// renamer returns the function renaming the ids of the builder with the given index.
+func (opts MergeOptions) renamer(i int, deps map[string][]string) func(string) string {
+	if i >= len(opts.Namespaces) || opts.Namespaces[i] == "" {
+		return func(id string) string { return id }
+	}
+	prefix := opts.Namespaces[i] + opts.Separator
+	if opts.Separator == "" {
+		prefix = opts.Namespaces[i] + "/"
+	}
+	return func(id string) string {
+		if _, ok := deps[id]; ok {
+			return prefix + id
+		}
+		return id
+	}
+}
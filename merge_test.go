@@ -0,0 +1,153 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newMergeSources() (*DepTreeBuilder, *DepTreeBuilder) {
+	a := NewDepTreeBuilder()
+	a.AddDeps("api", "db")
+	a.AddDeps("db")
+	a.SetCost("db", 3)
+	b := NewDepTreeBuilder()
+	b.AddDeps("web", "api")
+	b.AddDeps("db", "disk")
+	b.AddDeps("disk")
+	return a, b
+}
+
+func TestMergeWith(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    MergeOptions
+		want    map[string][]string
+		wantErr error
+	}{
+		{
+			name: "merge deps",
+			opts: MergeOptions{},
+			want: map[string][]string{"api": {"db"}, "db": {"disk"}, "web": {"api"}, "disk": {}},
+		},
+		{
+			name: "keep first",
+			opts: MergeOptions{Policy: DuplicateKeepFirst},
+			want: map[string][]string{"api": {"db"}, "db": {}, "web": {"api"}, "disk": {}},
+		},
+		{
+			name: "replace",
+			opts: MergeOptions{Policy: DuplicateReplace},
+			want: map[string][]string{"api": {"db"}, "db": {"disk"}, "web": {"api"}, "disk": {}},
+		},
+		{
+			name:    "error",
+			opts:    MergeOptions{Policy: DuplicateError},
+			wantErr: ErrDuplicateNode,
+		},
+		{
+			name: "namespaces",
+			opts: MergeOptions{Namespaces: []string{"", "b"}, Separator: "."},
+			want: map[string][]string{
+				"api": {"db"}, "db": {}, "b.web": {"api"}, "b.db": {"b.disk"}, "b.disk": {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newMergeSources()
+			got, err := MergeWith(tt.opts, a, b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MergeWith() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.deps, tt.want) {
+				t.Errorf("MergeWith() = %v, want %v", got.deps, tt.want)
+			}
+			if got.costs["db"] != 3 && tt.opts.Policy != DuplicateReplace {
+				t.Errorf("MergeWith() costs = %v", got.costs)
+			}
+		})
+	}
+}
+
+func TestMerge_CrossBuilderChecks(t *testing.T) {
+	a, b := newMergeSources()
+	a.AddDeps("disk", "web")
+	merged, err := Merge(a, b)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if _, err := merged.Build(); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("Build() error = %v, want %v", err, ErrIntegrity)
+	}
+}
+
+func TestMergeNWith(t *testing.T) {
+	a := NewNDepTreeBuilder[*testNode]()
+	a.AddNode(&testNode{nodeId: "api", deps: []string{"db"}})
+	a.AddNode(&testNode{nodeId: "db"})
+	b := NewNDepTreeBuilder[*testNode]()
+	dbB := &testNode{nodeId: "db"}
+	b.AddNode(dbB)
+	b.AddNode(&testNode{nodeId: "web", deps: []string{"a/api", "db"}})
+	if _, err := MergeNWith(MergeOptions{Policy: DuplicateError}, a, b); !errors.Is(err, ErrDuplicateNode) {
+		t.Fatalf("MergeNWith() error = %v, want %v", err, ErrDuplicateNode)
+	}
+	merged, err := MergeNWith(MergeOptions{Namespaces: []string{"a", "b"}}, a, b)
+	if err != nil {
+		t.Fatalf("MergeNWith() error = %v", err)
+	}
+	tree, err := merged.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	got := make([]string, 0)
+	for _, node := range tree.ListAscStr("b/web") {
+		got = append(got, node.NodeId())
+	}
+	if want := []string{"db", "db", "api", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAscStr() = %v, want %v", got, want)
+	}
+	if tree.nodes["b/db"] != dbB {
+		t.Errorf("MergeNWith() nodes = %v", tree.nodes)
+	}
+}
+
+func TestMergeWith_DuplicateMetadata(t *testing.T) {
+	a, b := newMergeSources()
+	b.SetCost("db", 9)
+	b.SetPriority("db", 2)
+	b.AddTags("db", "storage")
+	merged, err := Merge(a, b)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if merged.costs["db"] != 3 || len(merged.priorities) != 0 || len(merged.tags) != 0 {
+		t.Errorf("Merge() costs = %v, priorities = %v, tags = %v", merged.costs, merged.priorities, merged.tags)
+	}
+	if want := []string{"disk"}; !reflect.DeepEqual(merged.deps["db"], want) {
+		t.Errorf("Merge() deps = %v, want %v", merged.deps["db"], want)
+	}
+}
+
+func TestMergeNWith_PendingState(t *testing.T) {
+	a := NewNDepTreeBuilder[*testNode]()
+	a.AddNode(&testNode{nodeId: "db"})
+	b := NewNDepTreeBuilder[*testNode]()
+	b.SetDuplicatePolicy(DuplicateError)
+	b.AddNode(&testNode{nodeId: "api"})
+	b.AddNode(&testNode{nodeId: "api"})
+	merged, err := MergeN(a, b)
+	if err != nil {
+		t.Fatalf("MergeN() error = %v", err)
+	}
+	if merged.policy != DuplicateError {
+		t.Errorf("MergeN() policy = %v, want %v", merged.policy, DuplicateError)
+	}
+	if _, err := merged.Build(); !errors.Is(err, ErrDuplicateNode) {
+		t.Errorf("Build() error = %v, want %v", err, ErrDuplicateNode)
+	}
+}
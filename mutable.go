@@ -0,0 +1,237 @@
+package deptree
+
+import (
+	"fmt"
+	"strings"
+)
+
+var ErrNotFound = fmt.Errorf("not found")
+
+// MutableDepTree is a dependency tree which may be modified after it's built. Every modification validates only
+// the region of the tree it affects, so it doesn't cost as much as building the tree from scratch. The reachability
+// index is not maintained, DependsOn falls back to the search. MutableDepTree is not safe for concurrent use.
+type MutableDepTree struct {
+	DepTree
+	dependents map[string]map[string]bool
+}
+
+// BuildMutable builds a mutable dependency tree from the dependency tree builder. See Build for more details.
+func (dtb *DepTreeBuilder) BuildMutable() (*MutableDepTree, error) {
+	tree, err := dtb.Build()
+	if err != nil {
+		return nil, err
+	}
+	return tree.Mutable(), nil
+}
+
+// Mutable returns a mutable copy of the dependency tree.
+func (dt *DepTree) Mutable() *MutableDepTree {
+	tree := dt.derive(copyDeps(dt.deps))
+	tree.index = nil
+	mt := &MutableDepTree{DepTree: *tree, dependents: make(map[string]map[string]bool)}
+	for node, deps := range mt.deps {
+		for _, dep := range deps {
+			mt.addDependent(dep, node)
+		}
+	}
+	return mt
+}
+
+// Tree returns an immutable copy of the current state of the tree.
+func (mt *MutableDepTree) Tree() *DepTree {
+	return mt.derive(copyDeps(mt.deps))
+}
+
+// AddDeps adds dependencies to the node, the node is created if it doesn't exist. All dependencies must exist already.
+// Error is returned if a dependency is missing, the new dependencies create a cycle or make a node require two
+// conflicting nodes. A tree built with CondenseCycles accepts the cycles and condenses them again. The tree is not
+// modified if an error is returned.
+func (mt *MutableDepTree) AddDeps(node string, deps ...string) error {
+	for _, dep := range deps {
+		if _, ok := mt.deps[dep]; !ok && dep != node {
+			return fmt.Errorf("%w: missing dependency \"%s\"", ErrIntegrity, dep)
+		}
+		if mt.condensed {
+			continue
+		}
+		if ch := mt.pathTo(dep, node); ch != nil {
+			ch = append([]string{node}, ch...)
+			return fmt.Errorf("%w: cycle detected: %s", ErrIntegrity, strings.Join(ch, "->"))
+		}
+	}
+	_, existed := mt.deps[node]
+	previous := mt.deps[node]
+	added := make([]string, 0)
+	for _, dep := range deps {
+		if !contains(mt.deps[node], dep) && !contains(added, dep) {
+			added = append(added, dep)
+		}
+	}
+	mt.deps[node] = append(append(make([]string, 0, len(previous)+len(added)), previous...), added...)
+	for _, dep := range added {
+		mt.addDependent(dep, node)
+	}
+	if err := mt.conflictsCheckFor(node, added); err != nil {
+		for _, dep := range added {
+			delete(mt.dependents[dep], node)
+		}
+		mt.deps[node] = previous
+		if !existed {
+			delete(mt.deps, node)
+		}
+		return err
+	}
+	if mt.condensed {
+		mt.condense()
+	}
+	return nil
+}
+
+// RemoveNode removes the node from the tree. Error is returned if the node doesn't exist or other nodes still depend on
+// it.
+func (mt *MutableDepTree) RemoveNode(node string) error {
+	if _, ok := mt.deps[node]; !ok {
+		return fmt.Errorf("%w: node \"%s\"", ErrNotFound, node)
+	}
+	if dependents := sortedKeys(mt.dependents[node]); len(dependents) > 0 {
+		return fmt.Errorf("%w: node \"%s\" is required by \"%s\"", ErrIntegrity, node,
+			strings.Join(dependents, "\", \""))
+	}
+	for _, dep := range mt.deps[node] {
+		delete(mt.dependents[dep], node)
+	}
+	delete(mt.deps, node)
+	delete(mt.dependents, node)
+	delete(mt.conflicts, node)
+	delete(mt.costs, node)
+	delete(mt.typed, node)
+	delete(mt.tags, node)
+	delete(mt.via, node)
+	delete(mt.priorities, node)
+	if mt.condensed {
+		mt.condense()
+	}
+	return nil
+}
+
+// RemoveEdge removes the dependency of the node. Error is returned if the node doesn't depend on the dependency
+// directly.
+func (mt *MutableDepTree) RemoveEdge(node, dep string) error {
+	if !contains(mt.deps[node], dep) {
+		return fmt.Errorf("%w: dependency \"%s\" of \"%s\"", ErrNotFound, dep, node)
+	}
+	deps := make([]string, 0, len(mt.deps[node])-1)
+	for _, d := range mt.deps[node] {
+		if d != dep {
+			deps = append(deps, d)
+		}
+	}
+	mt.deps[node] = deps
+	if _, ok := mt.typed[node]; ok {
+		mt.typed[node] = withoutTypedDeps(mt.typed[node], deps)
+	}
+	delete(mt.via[node], dep)
+	delete(mt.dependents[dep], node)
+	if mt.condensed {
+		mt.condense()
+	}
+	return nil
+}
+
+func (mt *MutableDepTree) addDependent(dep, node string) {
+	if mt.dependents[dep] == nil {
+		mt.dependents[dep] = make(map[string]bool)
+	}
+	mt.dependents[dep][node] = true
+}
+
+// pathTo returns the chain of dependencies leading from the node to the target or nil if there is no such chain.
+// Only the nodes required by the node are visited.
+func (mt *MutableDepTree) pathTo(node, target string) []string {
+	if node == target {
+		return []string{node}
+	}
+	visited := map[string]bool{node: true}
+	parents := make(map[string]string)
+	stack := []string{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, dep := range mt.deps[current] {
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			parents[dep] = current
+			if dep == target {
+				chain := []string{dep}
+				for p, ok := current, true; ok; p, ok = parents[p] {
+					chain = append([]string{p}, chain...)
+				}
+				return chain
+			}
+			stack = append(stack, dep)
+		}
+	}
+	return nil
+}
+
+// conflictsCheckFor verifies that neither the node nor the nodes depending on it require two conflicting nodes after
+// the dependencies were added to the node. The tree had no conflicts before, so only the conflicts of the nodes pulled
+// by the added dependencies are checked against the nodes required by the node and its dependents.
+func (mt *MutableDepTree) conflictsCheckFor(node string, added []string) error {
+	if len(mt.conflicts) == 0 || len(added) == 0 {
+		return nil
+	}
+	ancestors := mt.ancestors(node)
+	pulled := reachable(mt.deps, added...)
+	required := reachable(mt.deps, ancestors...)
+	others := make([]string, 0)
+	for _, n := range sortedKeys(mt.conflicts) {
+		for _, other := range mt.conflicts[n] {
+			if other == n {
+				continue
+			}
+			if pulled[n] && required[other] {
+				others = append(others, other)
+			}
+			if pulled[other] && required[n] {
+				others = append(others, n)
+			}
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	requiring := make(map[string]bool)
+	for _, ancestor := range mt.ancestors(others...) {
+		requiring[ancestor] = true
+	}
+	for _, ancestor := range ancestors {
+		if requiring[ancestor] {
+			return mt.CheckConflicts(ancestor)
+		}
+	}
+	return nil
+}
+
+// ancestors returns the nodes and all nodes depending on them in breadth first order.
+func (mt *MutableDepTree) ancestors(nodes ...string) []string {
+	visited := make(map[string]bool, len(nodes))
+	queue := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if !visited[node] {
+			visited[node] = true
+			queue = append(queue, node)
+		}
+	}
+	for i := 0; i < len(queue); i++ {
+		for _, dependent := range sortedKeys(mt.dependents[queue[i]]) {
+			if !visited[dependent] {
+				visited[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return queue
+}
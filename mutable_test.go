@@ -0,0 +1,138 @@
+package deptree
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestMutable(t *testing.T) *MutableDepTree {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("a", "b")
+	builder.AddDeps("b", "c")
+	builder.AddDeps("c")
+	builder.AddDeps("mysql")
+	builder.AddDeps("mariadb")
+	builder.AddConflicts("mysql", "mariadb")
+	mt, err := builder.BuildMutable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return mt
+}
+
+func TestMutableDepTree_AddDeps(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    string
+		deps    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "new node", node: "d", deps: []string{"a"}, want: []string{"c", "b", "a", "d"}},
+		{name: "new edge", node: "c", deps: []string{"mysql"}, want: []string{"mysql", "c", "b", "a"}},
+		{name: "missing dependency", node: "d", deps: []string{"x"}, wantErr: ErrIntegrity},
+		{name: "cycle", node: "c", deps: []string{"a"}, wantErr: ErrIntegrity},
+		{name: "self cycle", node: "c", deps: []string{"c"}, wantErr: ErrIntegrity},
+		{name: "conflict", node: "b", deps: []string{"mysql", "mariadb"}, wantErr: ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mt := newTestMutable(t)
+			before := copyDeps(mt.deps)
+			err := mt.AddDeps(tt.node, tt.deps...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddDeps() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !reflect.DeepEqual(mt.deps, before) {
+					t.Errorf("AddDeps() modified the tree: %v, want %v", mt.deps, before)
+				}
+				return
+			}
+			top := tt.node
+			if top == "c" {
+				top = "a"
+			}
+			if got := mt.ListAsc(top); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAsc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMutableDepTree_AddDepsCycleChain(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("a", "b")
+	builder.AddDeps("b")
+	builder.AddDeps("", "a")
+	mt, err := builder.BuildMutable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "integrity error: cycle detected: b->->a->b"
+	if err := mt.AddDeps("b", ""); !errors.Is(err, ErrIntegrity) || err.Error() != want {
+		t.Errorf("AddDeps() error = %v, want %s", err, want)
+	}
+}
+
+func TestMutableDepTree_AddDepsConflictsLargeTree(t *testing.T) {
+	const n = 20000
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("0")
+	for i := 1; i < n; i++ {
+		builder.AddDeps(fmt.Sprint(i), fmt.Sprint(i-1))
+	}
+	builder.AddDeps("mysql")
+	builder.AddDeps("mariadb")
+	builder.AddConflicts("mysql", "mariadb")
+	mt, err := builder.BuildMutable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now()
+	if err := mt.AddDeps("0", "mysql"); err != nil {
+		t.Fatalf("AddDeps() unexpected error: %v", err)
+	}
+	var conflict *ConflictError
+	if err := mt.AddDeps("10", "mariadb"); !errors.As(err, &conflict) || !reflect.DeepEqual(conflict.NodeTops, []string{"10"}) {
+		t.Errorf("AddDeps() error = %v, want conflict required by \"10\"", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("AddDeps() took %v on %d nodes, want the conflicts checked once per edit", elapsed, n)
+	}
+}
+
+func TestMutableDepTree_Remove(t *testing.T) {
+	mt := newTestMutable(t)
+	if err := mt.RemoveNode("x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveNode() error = %v, want %v", err, ErrNotFound)
+	}
+	if err := mt.RemoveNode("c"); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("RemoveNode() error = %v, want %v", err, ErrIntegrity)
+	}
+	if err := mt.RemoveEdge("a", "c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RemoveEdge() error = %v, want %v", err, ErrNotFound)
+	}
+	if err := mt.RemoveEdge("b", "c"); err != nil {
+		t.Fatalf("RemoveEdge() error = %v", err)
+	}
+	if err := mt.RemoveNode("c"); err != nil {
+		t.Fatalf("RemoveNode() error = %v", err)
+	}
+	if got, want := mt.ListAsc("a"), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+	if err := mt.AddDeps("b", "a"); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("AddDeps() error = %v, want %v", err, ErrIntegrity)
+	}
+	tree := mt.Tree()
+	if err := mt.AddDeps("e", "a"); err != nil {
+		t.Fatalf("AddDeps() error = %v", err)
+	}
+	if got := tree.ListAsc("e"); len(got) != 0 {
+		t.Errorf("Tree() shares state with the mutable tree: %v", got)
+	}
+}
@@ -1,5 +1,7 @@
 package deptree
 
+import "fmt"
+
 // NDepTreeBuilder collects nodes needed to build a dependency tree. The node is an object implementing Node interface.
 // The difference between IDepTreeBuilder and NDepTreeBuilder is that IDepTreeBuilder may be used for
 // object of different types implementing Node interface, but then the client code must be aware of the type of
@@ -8,6 +10,8 @@ package deptree
 type NDepTreeBuilder[N Node] struct {
 	nodes   map[string]N
 	builder *DepTreeBuilder
+	policy  DuplicatePolicy
+	err     error
 }
 
 // NewNDepTreeBuilder returns a new NDepTreeBuilder.
@@ -18,20 +22,113 @@ func NewNDepTreeBuilder[N Node]() *NDepTreeBuilder[N] {
 	}
 }
 
-// AddNode adds a node to the NDepTreeBuilder.
+// AddNode adds a node to the NDepTreeBuilder. If a node with the same id is already added, the duplicate policy
+// decides what happens, see SetDuplicatePolicy. With the DuplicateError policy the duplicate is ignored and the first
+// DuplicateNodeError is returned by Build. Use TryAddNode to get the error right away.
 func (dtb *NDepTreeBuilder[N]) AddNode(node N) {
-	if _, ok := dtb.nodes[node.NodeId()]; !ok {
-		dtb.nodes[node.NodeId()] = node
+	if err := dtb.TryAddNode(node); err != nil && dtb.err == nil {
+		dtb.err = err
+	}
+}
+
+// TryAddNode works like AddNode, but the DuplicateNodeError of the DuplicateError policy is returned right away and
+// Build doesn't fail because of it.
+func (dtb *NDepTreeBuilder[N]) TryAddNode(node N) error {
+	id := node.NodeId()
+	if existing, ok := dtb.nodes[id]; ok {
+		switch dtb.policy {
+		case DuplicateError:
+			return &DuplicateNodeError{Id: id, Existing: existing, Duplicate: node}
+		case DuplicateKeepFirst:
+			return nil
+		case DuplicateReplace:
+			dtb.nodes[id] = node
+			dtb.builder.ReplaceDeps(id, node.Deps()...)
+			dtb.builder.dropMetadata(id)
+			dtb.addMetadata(node)
+			return nil
+		default:
+			dtb.builder.AddDeps(id, node.Deps()...)
+			return nil
+		}
+	}
+	dtb.nodes[id] = node
+	dtb.builder.AddDeps(id, node.Deps()...)
+	dtb.addMetadata(node)
+	return nil
+}
+
+// SetDuplicatePolicy sets the policy used by AddNode when a node with the same id is added again.
+// The default policy is DuplicateMergeDeps.
+func (dtb *NDepTreeBuilder[N]) SetDuplicatePolicy(policy DuplicatePolicy) {
+	dtb.policy = policy
+}
+
+// AllowEmptyPatterns makes the pattern dependencies matching no nodes allowed. See DepTreeBuilder.AllowEmptyPatterns.
+func (dtb *NDepTreeBuilder[N]) AllowEmptyPatterns() {
+	dtb.builder.AllowEmptyPatterns()
+}
+
+// addMetadata adds the metadata provided by the optional interfaces of the node.
+func (dtb *NDepTreeBuilder[N]) addMetadata(node N) {
+	if c, ok := any(node).(Conflicter); ok {
+		dtb.builder.AddConflicts(node.NodeId(), c.Conflicts()...)
+	}
+	if c, ok := any(node).(Coster); ok {
+		dtb.builder.SetCost(node.NodeId(), c.Cost())
+	}
+	if t, ok := any(node).(TypedDeper); ok {
+		dtb.builder.AddTypedDeps(node.NodeId(), t.TypedDeps()...)
+	}
+	if t, ok := any(node).(Tagger); ok {
+		dtb.builder.AddTags(node.NodeId(), t.Tags()...)
+	}
+	if p, ok := any(node).(Prioritizer); ok {
+		dtb.builder.SetPriority(node.NodeId(), p.Priority())
+	}
+	if p, ok := any(node).(PatternDeper); ok {
+		dtb.builder.AddPatternDeps(node.NodeId(), p.PatternDeps()...)
 	}
-	dtb.builder.AddDeps(node.NodeId(), node.Deps()...)
 }
 
 // Build builds a dependency tree from the NDepTreeBuilder. If node for a dependency is not added to the NDepTreeBuilder
 // an integrity error will be returned.
 func (dtb *NDepTreeBuilder[N]) Build() (*NDepTree[N], error) {
+	if dtb.err != nil {
+		return nil, dtb.err
+	}
 	tree, err := dtb.builder.Build()
 	if err != nil {
 		return nil, err
 	}
-	return &NDepTree[N]{nodes: dtb.nodes, tree: tree}, nil
+	nodes := make(map[string]N, len(dtb.nodes))
+	for id, node := range dtb.nodes {
+		nodes[id] = node
+	}
+	return &NDepTree[N]{nodes: nodes, tree: tree}, nil
+}
+
+// RemoveNode removes the node with the given id. In RemoveCascade mode the nodes requiring the node are removed as well.
+// See DepTreeBuilder.RemoveNode for more details.
+func (dtb *NDepTreeBuilder[N]) RemoveNode(id string, mode RemoveMode) error {
+	removed, err := dtb.builder.removeNode(id, mode)
+	for _, r := range removed {
+		delete(dtb.nodes, r)
+	}
+	return err
+}
+
+// RemoveDep removes the dependency of the node with the given id. The node object is not modified.
+func (dtb *NDepTreeBuilder[N]) RemoveDep(id, dep string) error {
+	return dtb.builder.RemoveDep(id, dep)
+}
+
+// ReplaceDeps replaces all dependencies of the node with the given id. The node object is not modified.
+// Error is returned if the node is not added.
+func (dtb *NDepTreeBuilder[N]) ReplaceDeps(id string, deps ...string) error {
+	if _, ok := dtb.nodes[id]; !ok {
+		return fmt.Errorf("%w: node \"%s\"", ErrNotFound, id)
+	}
+	dtb.builder.ReplaceDeps(id, deps...)
+	return nil
 }
@@ -1,6 +1,7 @@
 package deptree
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -93,3 +94,40 @@ func TestNewNDepTreeBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestNDepTreeBuilder_Remove(t *testing.T) {
+	builder := NewNDepTreeBuilder[*testNode]()
+	for _, node := range []*testNode{
+		{nodeId: "a", deps: []string{"b"}},
+		{nodeId: "b", deps: []string{"c"}},
+		{nodeId: "c"},
+		{nodeId: "d"},
+	} {
+		builder.AddNode(node)
+	}
+	if err := builder.ReplaceDeps("x", "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReplaceDeps() error = %v, want %v", err, ErrNotFound)
+	}
+	if err := builder.ReplaceDeps("d", "c"); err != nil {
+		t.Fatalf("ReplaceDeps() error = %v", err)
+	}
+	if err := builder.RemoveNode("c", RemoveStrict); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("RemoveNode() error = %v, want %v", err, ErrIntegrity)
+	}
+	if err := builder.RemoveDep("d", "c"); err != nil {
+		t.Fatalf("RemoveDep() error = %v", err)
+	}
+	if err := builder.RemoveNode("b", RemoveCascade); err != nil {
+		t.Fatalf("RemoveNode() error = %v", err)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := sortedKeys(tree.nodes), []string{"c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveNode() nodes = %v, want %v", got, want)
+	}
+	if got, want := sortedKeys(tree.tree.deps), []string{"c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveNode() deps = %v, want %v", got, want)
+	}
+}
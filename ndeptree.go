@@ -6,7 +6,7 @@ type Node interface {
 	Deps() []string
 }
 
-// NDepTree is an object sorting dependencies to the lists
+// NDepTree is an object sorting dependencies to the lists. It's safe for concurrent use.
 type NDepTree[N Node] struct {
 	nodes map[string]N
 	tree  *DepTree
@@ -20,12 +20,7 @@ func (dt *NDepTree[N]) ListAsc(top ...N) []N {
 
 // ListAscStr takes strings representing node ids. See ListAsc for more details.
 func (dt *NDepTree[N]) ListAscStr(top ...string) []N {
-	ls := dt.tree.ListAsc(top...)
-	result := make([]N, len(ls))
-	for i, l := range ls {
-		result[i] = dt.nodes[l]
-	}
-	return result
+	return dt.list(dt.tree.ListAsc(top...))
 }
 
 // ListDesc sorts the dependencies bases on provided top nodes. Descending order means that dependency comes after
@@ -44,10 +39,45 @@ func (dt *NDepTree[N]) stringify(nodes []N) []string {
 
 // ListDescStr takes strings representing node ids. See ListDesc for more details.
 func (dt *NDepTree[N]) ListDescStr(top ...string) []N {
-	ls := dt.tree.ListDesc(top...)
-	result := make([]N, len(ls))
-	for i, l := range ls {
-		result[i] = dt.nodes[l]
+	return dt.list(dt.tree.ListDesc(top...))
+}
+
+func (dt *NDepTree[N]) list(ids []string) []N {
+	result := make([]N, len(ids))
+	for i, id := range ids {
+		result[i] = dt.nodes[id]
 	}
 	return result
 }
+
+// CheckConflicts verifies that the nodes required by the given tops don't conflict with each other.
+// See DepTree.CheckConflicts for more details.
+func (dt *NDepTree[N]) CheckConflicts(top ...N) error {
+	return dt.tree.CheckConflicts(dt.stringify(top)...)
+}
+
+// CheckConflictsStr takes strings representing node ids. See CheckConflicts for more details.
+func (dt *NDepTree[N]) CheckConflictsStr(top ...string) error {
+	return dt.tree.CheckConflicts(top...)
+}
+
+// CriticalPath returns the heaviest dependency chain of the nodes required by the given tops.
+// See DepTree.CriticalPath for more details.
+func (dt *NDepTree[N]) CriticalPath(top ...N) *CriticalPath {
+	return dt.tree.CriticalPath(dt.stringify(top)...)
+}
+
+// CriticalPathStr takes strings representing node ids. See CriticalPath for more details.
+func (dt *NDepTree[N]) CriticalPathStr(top ...string) *CriticalPath {
+	return dt.tree.CriticalPath(top...)
+}
+
+// Schedule assigns the nodes required by the given tops to the workers. See DepTree.Schedule for more details.
+func (dt *NDepTree[N]) Schedule(workers int, top ...N) (*Schedule, error) {
+	return dt.tree.Schedule(workers, dt.stringify(top)...)
+}
+
+// ScheduleStr takes strings representing node ids. See Schedule for more details.
+func (dt *NDepTree[N]) ScheduleStr(workers int, top ...string) (*Schedule, error) {
+	return dt.tree.Schedule(workers, top...)
+}
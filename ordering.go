@@ -0,0 +1,134 @@
+package deptree
+
+import "container/heap"
+
+// Ordering decides the order of the nodes not depending on each other in ListAsc and ListDesc. All orderings list
+// a dependency before the node.
+type Ordering int
+
+const (
+	// OrderDepthFirst is the default ordering. The nodes are listed depth first, the nodes required by the later tops
+	// and dependencies come earlier.
+	OrderDepthFirst Ordering = iota
+	// OrderPriority lists the nodes with all dependencies listed by the priority, the highest first. Nodes with equal
+	// priorities are listed in the order of the ids. See SetPriority and Prioritizer.
+	OrderPriority
+	// OrderCanonical lists the lexicographically smallest order of the ids. The order depends only on the nodes and
+	// the dependencies required by the tops, not on the order of the tops or the dependencies, so it's suitable for
+	// generated files like lockfiles.
+	OrderCanonical
+)
+
+// Prioritizer is an optional interface for a Node. If the node implements it, the builders set the returned
+// priority of the node. Nodes without the priority have the priority 0.
+type Prioritizer interface {
+	Priority() int
+}
+
+// SetPriority sets the priority of the node used by OrderPriority.
+func (dtb *DepTreeBuilder) SetPriority(node string, priority int) {
+	if dtb.priorities == nil {
+		dtb.priorities = make(map[string]int)
+	}
+	dtb.priorities[node] = priority
+}
+
+// SetOrdering sets the ordering of the built tree. The default ordering is OrderDepthFirst.
+func (dtb *DepTreeBuilder) SetOrdering(ordering Ordering) {
+	dtb.ordering = ordering
+}
+
+// SetOrdering sets the ordering of the built tree. See DepTreeBuilder.SetOrdering for more details.
+func (dtb *NDepTreeBuilder[N]) SetOrdering(ordering Ordering) {
+	dtb.builder.SetOrdering(ordering)
+}
+
+// SetOrdering sets the ordering of the built tree. See DepTreeBuilder.SetOrdering for more details.
+func (dtb *IDepTreeBuilder) SetOrdering(ordering Ordering) {
+	(*NDepTreeBuilder[Node])(dtb).SetOrdering(ordering)
+}
+
+// order returns the nodes required by the tops in ascending order of the tree's ordering. The nodes of the same
+// cycle are listed together, see CondenseCycles.
+func (dt *DepTree) order(lookup func(node string) ([]string, bool), top []string) []string {
+	if len(dt.groups) == 0 {
+		return dt.orderNodes(lookup, top)
+	}
+	result := make([]string, 0)
+	for _, group := range dt.orderGroups(lookup, top) {
+		result = append(result, group...)
+	}
+	return result
+}
+
+// orderNodes returns the nodes required by the tops in ascending order of the tree's ordering.
+func (dt *DepTree) orderNodes(lookup func(node string) ([]string, bool), top []string) []string {
+	switch dt.ordering {
+	case OrderPriority:
+		return readyOrder(lookup, top, func(a, b string) bool {
+			if dt.priorities[a] != dt.priorities[b] {
+				return dt.priorities[a] > dt.priorities[b]
+			}
+			return a < b
+		})
+	case OrderCanonical:
+		return readyOrder(lookup, top, func(a, b string) bool {
+			return a < b
+		})
+	default:
+		return postorder(lookup, top)
+	}
+}
+
+// readyOrder returns the nodes required by the tops in ascending order. Of the nodes with all dependencies listed,
+// the least one is listed first. Missing nodes are skipped.
+func readyOrder(lookup func(node string) ([]string, bool), top []string, less func(a, b string) bool) []string {
+	nodes := postorder(lookup, top)
+	waiting := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		waiting[node] = 0
+	}
+	dependents := make(map[string][]string)
+	for _, node := range nodes {
+		deps, _ := lookup(node)
+		for _, dep := range deps {
+			if _, ok := waiting[dep]; ok {
+				waiting[node]++
+				dependents[dep] = append(dependents[dep], node)
+			}
+		}
+	}
+	ready := &readyHeap{less: less}
+	for _, node := range nodes {
+		if waiting[node] == 0 {
+			ready.ids = append(ready.ids, node)
+		}
+	}
+	heap.Init(ready)
+	result := make([]string, 0, len(nodes))
+	for ready.Len() > 0 {
+		node := heap.Pop(ready).(string)
+		result = append(result, node)
+		for _, dependent := range dependents[node] {
+			if waiting[dependent]--; waiting[dependent] == 0 {
+				heap.Push(ready, dependent)
+			}
+		}
+	}
+	return result
+}
+
+type readyHeap struct {
+	ids  []string
+	less func(a, b string) bool
+}
+
+func (h *readyHeap) Len() int           { return len(h.ids) }
+func (h *readyHeap) Less(i, j int) bool { return h.less(h.ids[i], h.ids[j]) }
+func (h *readyHeap) Swap(i, j int)      { h.ids[i], h.ids[j] = h.ids[j], h.ids[i] }
+func (h *readyHeap) Push(x any)         { h.ids = append(h.ids, x.(string)) }
+func (h *readyHeap) Pop() any {
+	last := h.ids[len(h.ids)-1]
+	h.ids = h.ids[:len(h.ids)-1]
+	return last
+}
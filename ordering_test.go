@@ -0,0 +1,225 @@
+package deptree
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+type priorityNode struct {
+	testNode
+	priority int
+}
+
+func (pn *priorityNode) Priority() int {
+	return pn.priority
+}
+
+func TestDepTree_ListAscPriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		deps       map[string][]string
+		priorities map[string]int
+		top        []string
+		want       []string
+	}{
+		{
+			name:       "higher priority first",
+			deps:       map[string][]string{"app": {"migrations", "health", "cache"}, "migrations": {}, "health": {}, "cache": {}},
+			priorities: map[string]int{"migrations": 10, "health": 5},
+			top:        []string{"app"},
+			want:       []string{"migrations", "health", "cache", "app"},
+		},
+		{
+			name:       "dependencies respected",
+			deps:       map[string][]string{"app": {"urgent", "db"}, "urgent": {"db"}, "db": {}, "log": {}},
+			priorities: map[string]int{"urgent": 100, "log": 1},
+			top:        []string{"app", "log"},
+			want:       []string{"log", "db", "urgent", "app"},
+		},
+		{
+			name:       "ties by id",
+			deps:       map[string][]string{"c": {}, "a": {}, "b": {}},
+			priorities: map[string]int{},
+			top:        []string{"c", "b", "a"},
+			want:       []string{"a", "b", "c"},
+		},
+		{
+			name:       "negative priority last",
+			deps:       map[string][]string{"a": {}, "b": {}, "c": {}},
+			priorities: map[string]int{"a": -1},
+			top:        []string{"a", "b", "c", "missing"},
+			want:       []string{"b", "c", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewDepTreeBuilder()
+			for node, deps := range tt.deps {
+				builder.AddDeps(node, deps...)
+			}
+			for node, priority := range tt.priorities {
+				builder.SetPriority(node, priority)
+			}
+			builder.SetOrdering(OrderPriority)
+			tree, err := builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := tree.ListAsc(tt.top...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAsc() = %v, want %v", got, tt.want)
+			}
+			if got := tree.ListDesc(tt.top...); !reflect.DeepEqual(got, reversed(tt.want)) {
+				t.Errorf("ListDesc() = %v, want %v", got, reversed(tt.want))
+			}
+		})
+	}
+}
+
+func TestNDepTree_ListAscPriority(t *testing.T) {
+	app := &priorityNode{testNode: testNode{nodeId: "app", deps: []string{"health", "migrate", "assets"}}}
+	health := &priorityNode{testNode: testNode{nodeId: "health"}, priority: 1}
+	migrate := &priorityNode{testNode: testNode{nodeId: "migrate"}, priority: 2}
+	assets := &priorityNode{testNode: testNode{nodeId: "assets"}}
+	builder := NewNDepTreeBuilder[*priorityNode]()
+	for _, node := range []*priorityNode{app, health, migrate, assets} {
+		builder.AddNode(node)
+	}
+	builder.SetOrdering(OrderPriority)
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*priorityNode{migrate, health, assets, app}
+	if got := tree.ListAsc(app); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+	sub := tree.Subtree(app)
+	if got := sub.ListAsc(app); !reflect.DeepEqual(got, want) {
+		t.Errorf("Subtree().ListAsc() = %v, want %v", got, want)
+	}
+}
+
+func TestIDepTree_ListAscPriority(t *testing.T) {
+	builder := NewIDepTreeBuilder()
+	builder.AddNode(&priorityNode{testNode: testNode{nodeId: "a", deps: []string{"b", "c"}}})
+	builder.AddNode(&priorityNode{testNode: testNode{nodeId: "b"}})
+	builder.AddNode(&priorityNode{testNode: testNode{nodeId: "c"}, priority: 1})
+	builder.SetOrdering(OrderPriority)
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make([]string, 0)
+	for _, node := range tree.ListAscStr("a") {
+		got = append(got, node.NodeId())
+	}
+	if want := []string{"c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAscStr() = %v, want %v", got, want)
+	}
+}
+
+func TestDepTree_ListAscCanonical(t *testing.T) {
+	deps := map[string][]string{
+		"app":    {"web", "db", "auth"},
+		"web":    {"assets", "auth"},
+		"auth":   {"db", "crypto"},
+		"db":     {"crypto"},
+		"crypto": {},
+		"assets": {},
+		"zlib":   {},
+	}
+	want := []string{"assets", "crypto", "db", "auth", "web", "app"}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		builder := NewDepTreeBuilder()
+		for node, ds := range deps {
+			shuffled := append([]string{}, ds...)
+			rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			builder.AddDeps(node, shuffled...)
+		}
+		builder.SetOrdering(OrderCanonical)
+		tree, err := builder.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := tree.ListAsc("app"); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ListAsc() = %v, want %v", got, want)
+		}
+		if got := tree.ListAsc("web", "app", "db"); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ListAsc() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestDepTree_ListAscCanonicalSmallest(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		deps := randomDeps(rng, 7)
+		builder := NewDepTreeBuilder()
+		for node, ds := range deps {
+			builder.AddDeps(node, ds...)
+		}
+		builder.SetOrdering(OrderCanonical)
+		tree, err := builder.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		top := sortedKeys(deps)
+		want := smallestOrder(deps, nil, map[string]bool{})
+		if got := tree.ListAsc(top...); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ListAsc() = %v, want %v for %v", got, want, deps)
+		}
+	}
+}
+
+// randomDeps returns an acyclic graph of n nodes, a node depends only on the nodes with lower numbers.
+func randomDeps(rng *rand.Rand, n int) map[string][]string {
+	deps := make(map[string][]string)
+	for i := 0; i < n; i++ {
+		node := string(rune('a' + rng.Intn(26)))
+		for _, ok := deps[node]; ok; _, ok = deps[node] {
+			node = string(rune('a' + rng.Intn(26)))
+		}
+		deps[node] = make([]string, 0)
+		for _, dep := range sortedKeys(deps) {
+			if dep != node && rng.Intn(3) == 0 {
+				deps[node] = append(deps[node], dep)
+			}
+		}
+	}
+	return deps
+}
+
+// smallestOrder returns the lexicographically smallest of all topological orders by trying all of them.
+func smallestOrder(deps map[string][]string, prefix []string, listed map[string]bool) []string {
+	if len(prefix) == len(deps) {
+		return append([]string{}, prefix...)
+	}
+	var best []string
+	for _, node := range sortedKeys(deps) {
+		ready := !listed[node]
+		for _, dep := range deps[node] {
+			ready = ready && listed[dep]
+		}
+		if !ready {
+			continue
+		}
+		listed[node] = true
+		order := smallestOrder(deps, append(prefix, node), listed)
+		listed[node] = false
+		if best == nil || lessOrder(order, best) {
+			best = order
+		}
+	}
+	return best
+}
+
+func lessOrder(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
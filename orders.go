@@ -0,0 +1,205 @@
+package deptree
+
+import (
+	"math"
+	"math/rand"
+)
+
+// OrderIterator enumerates all valid ascending orders of the nodes required by the tops lazily, in the lexicographic
+// order of the ids. The number of the orders grows very fast with the number of independent nodes, so it's meant for
+// small trees. The nodes of a cycle condensed by CondenseCycles are always listed together in the order of the ids, like
+// by ListAscGroups, so the orders differ only in the order of the groups. The iterator is not safe for concurrent use.
+type OrderIterator struct {
+	graph   *orderGraph
+	frames  []orderFrame
+	prefix  []int
+	started bool
+}
+
+type orderFrame struct {
+	candidates []int
+	chosen     int
+}
+
+// Orders returns an iterator over all valid ascending orders of the nodes required by the tops. Missing tops are
+// skipped.
+func (dt *DepTree) Orders(top ...string) *OrderIterator {
+	return &OrderIterator{graph: dt.orderGraph(top)}
+}
+
+// Next advances the iterator to the next order. It returns false if there are no more orders.
+func (it *OrderIterator) Next() bool {
+	g := it.graph
+	if !it.started {
+		it.started = true
+		it.frames = []orderFrame{{candidates: g.ready(), chosen: -1}}
+		if len(g.nodes) == 0 {
+			it.frames = nil
+			return true
+		}
+	}
+	for len(it.frames) > 0 {
+		f := &it.frames[len(it.frames)-1]
+		if f.chosen >= 0 {
+			g.unlist(f.candidates[f.chosen])
+			it.prefix = it.prefix[:len(it.prefix)-1]
+		}
+		f.chosen++
+		if f.chosen == len(f.candidates) {
+			it.frames = it.frames[:len(it.frames)-1]
+			continue
+		}
+		node := f.candidates[f.chosen]
+		g.list(node)
+		it.prefix = append(it.prefix, node)
+		if len(it.prefix) == len(g.nodes) {
+			return true
+		}
+		it.frames = append(it.frames, orderFrame{candidates: g.ready(), chosen: -1})
+	}
+	return false
+}
+
+// Order returns the current order. It's valid after Next returned true.
+func (it *OrderIterator) Order() []string {
+	result := make([]string, 0, len(it.prefix))
+	for _, node := range it.prefix {
+		result = append(result, it.graph.members[node]...)
+	}
+	return result
+}
+
+// CountOrders counts the valid ascending orders of the nodes required by the tops. The counting stops at the limit,
+// the returned bool is false if there are more orders than the limit. The groups of the condensed cycles count as
+// single nodes, see Orders. A limit lower than 1 means no limit, then the
+// count saturates at math.MaxInt.
+func (dt *DepTree) CountOrders(limit int, top ...string) (int, bool) {
+	bound := limit + 1
+	if limit < 1 || limit == math.MaxInt {
+		bound = math.MaxInt
+	}
+	g := dt.orderGraph(top)
+	memo := make(map[string]int)
+	var count func() int
+	count = func() int {
+		key := g.key()
+		if c, ok := memo[key]; ok {
+			return c
+		}
+		ready := g.ready()
+		c := 0
+		if g.done == len(g.nodes) {
+			c = 1
+		}
+		for _, node := range ready {
+			g.list(node)
+			n := count()
+			g.unlist(node)
+			if c > bound-n {
+				c = bound
+				break
+			}
+			c += n
+		}
+		memo[key] = c
+		return c
+	}
+	c := count()
+	if c == bound && bound != math.MaxInt {
+		return limit, false
+	}
+	return c, true
+}
+
+// RandomOrder returns a random valid ascending order of the nodes required by the tops. It's handy for testing code
+// that should not depend on the order. Every valid order may be returned, but not all with the same probability.
+// The groups of the condensed cycles are listed like by Orders.
+func (dt *DepTree) RandomOrder(rng *rand.Rand, top ...string) []string {
+	g := dt.orderGraph(top)
+	result := make([]string, 0, len(g.nodes))
+	for ready := g.ready(); len(ready) > 0; ready = g.ready() {
+		node := ready[rng.Intn(len(ready))]
+		g.list(node)
+		result = append(result, g.members[node]...)
+	}
+	return result
+}
+
+// orderGraph is the condensed subgraph required by the tops with the nodes numbered in the order of the ids. A node is
+// a group of the nodes in a cycle represented by its least id or a single node. It tracks which nodes are already
+// listed.
+type orderGraph struct {
+	nodes      []string
+	members    [][]string
+	dependents [][]int
+	waiting    []int
+	listed     []bool
+	done       int
+}
+
+func (dt *DepTree) orderGraph(top []string) *orderGraph {
+	required := make(map[string]bool)
+	for _, node := range dt.condensedOrder(top) {
+		required[node] = true
+	}
+	lookup := dt.condensedLookup()
+	nodes := sortedKeys(required)
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		index[node] = i
+	}
+	g := &orderGraph{
+		nodes:      nodes,
+		members:    make([][]string, len(nodes)),
+		dependents: make([][]int, len(nodes)),
+		waiting:    make([]int, len(nodes)),
+		listed:     make([]bool, len(nodes)),
+	}
+	for i, node := range nodes {
+		g.members[i] = dt.members(node)
+		deps, _ := lookup(node)
+		for _, dep := range deps {
+			g.dependents[index[dep]] = append(g.dependents[index[dep]], i)
+			g.waiting[i]++
+		}
+	}
+	return g
+}
+
+// ready returns the nodes not listed yet with all dependencies listed.
+func (g *orderGraph) ready() []int {
+	result := make([]int, 0)
+	for i := range g.nodes {
+		if !g.listed[i] && g.waiting[i] == 0 {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (g *orderGraph) list(node int) {
+	g.listed[node] = true
+	g.done++
+	for _, dependent := range g.dependents[node] {
+		g.waiting[dependent]--
+	}
+}
+
+func (g *orderGraph) unlist(node int) {
+	g.listed[node] = false
+	g.done--
+	for _, dependent := range g.dependents[node] {
+		g.waiting[dependent]++
+	}
+}
+
+// key returns the set of the listed nodes as a string.
+func (g *orderGraph) key() string {
+	key := make([]byte, (len(g.nodes)+7)/8)
+	for i, listed := range g.listed {
+		if listed {
+			key[i/8] |= 1 << (i % 8)
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,111 @@
+package deptree
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDepTree_Orders(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "api", "ui")
+	builder.AddDeps("api", "db")
+	builder.AddDeps("ui")
+	builder.AddDeps("db")
+	builder.AddDeps("other")
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]string{
+		{"db", "api", "ui", "app"},
+		{"db", "ui", "api", "app"},
+		{"ui", "db", "api", "app"},
+	}
+	got := make([][]string, 0)
+	for it := tree.Orders("app"); it.Next(); {
+		got = append(got, it.Order())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Orders() = %v, want %v", got, want)
+	}
+	if count, exact := tree.CountOrders(0, "app"); count != 3 || !exact {
+		t.Errorf("CountOrders() = %v, %v, want %v, %v", count, exact, 3, true)
+	}
+	if count, exact := tree.CountOrders(3, "app"); count != 3 || !exact {
+		t.Errorf("CountOrders() = %v, %v, want %v, %v", count, exact, 3, true)
+	}
+	if count, exact := tree.CountOrders(2, "app"); count != 2 || exact {
+		t.Errorf("CountOrders() = %v, %v, want %v, %v", count, exact, 2, false)
+	}
+	it := tree.Orders("missing")
+	if !it.Next() || len(it.Order()) != 0 || it.Next() {
+		t.Errorf("Orders() of missing top should return one empty order")
+	}
+}
+
+func TestDepTree_OrdersRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		deps := randomDeps(rng, 6)
+		builder := NewDepTreeBuilder()
+		for node, ds := range deps {
+			builder.AddDeps(node, ds...)
+		}
+		tree, err := builder.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		top := sortedKeys(deps)
+		seen := make(map[string]bool)
+		for it := tree.Orders(top...); it.Next(); {
+			order := it.Order()
+			if !validOrder(deps, order) {
+				t.Fatalf("Orders() returned invalid order %v for %v", order, deps)
+			}
+			seen[strings.Join(order, ",")] = true
+		}
+		if count, _ := tree.CountOrders(0, top...); count != len(seen) {
+			t.Fatalf("CountOrders() = %v, want %v for %v", count, len(seen), deps)
+		}
+		if order := tree.RandomOrder(rng, top...); !seen[strings.Join(order, ",")] {
+			t.Fatalf("RandomOrder() = %v is not a valid order of %v", order, deps)
+		}
+	}
+}
+
+func TestDepTree_CountOrdersLimit(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	top := make([]string, 0)
+	for i := 0; i < 40; i++ {
+		node := string(rune('A' + i))
+		builder.AddDeps(node)
+		top = append(top, node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, exact := tree.CountOrders(1000, top...); count != 1000 || exact {
+		t.Errorf("CountOrders() = %v, %v, want %v, %v", count, exact, 1000, false)
+	}
+}
+
+func validOrder(deps map[string][]string, order []string) bool {
+	position := make(map[string]int)
+	for i, node := range order {
+		position[node] = i
+	}
+	if len(position) != len(deps) {
+		return false
+	}
+	for node, ds := range deps {
+		for _, dep := range ds {
+			if position[dep] >= position[node] {
+				return false
+			}
+		}
+	}
+	return true
+}
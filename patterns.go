@@ -0,0 +1,116 @@
+package deptree
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PatternDeper is an optional interface for a Node. If the node implements it, the builders add the returned patterns
+// as pattern dependencies of the node, see DepTreeBuilder.AddPatternDeps.
+type PatternDeper interface {
+	PatternDeps() []string
+}
+
+// AddPatternDeps adds dependencies on all nodes matching the glob patterns, see path.Match. The patterns are expanded
+// when the tree is built against all node ids known to the builder, the node itself is never matched. A pattern
+// matching no nodes violates the integrity unless AllowEmptyPatterns is called.
+func (dtb *DepTreeBuilder) AddPatternDeps(node string, patterns ...string) {
+	dtb.AddDeps(node)
+	if dtb.patterns == nil {
+		dtb.patterns = make(map[string][]string)
+	}
+	for _, pattern := range patterns {
+		if !contains(dtb.patterns[node], pattern) {
+			dtb.patterns[node] = append(dtb.patterns[node], pattern)
+		}
+	}
+}
+
+// AllowEmptyPatterns makes the patterns matching no nodes expand to no dependencies instead of violating the
+// integrity.
+func (dtb *DepTreeBuilder) AllowEmptyPatterns() {
+	dtb.allowEmptyPatterns = true
+}
+
+// expandPatterns returns a copy of the dependencies with the patterns expanded and the patterns the expanded
+// dependencies come from. A dependency added directly is kept as it is even if a pattern matches it too.
+func (dtb *DepTreeBuilder) expandPatterns() (map[string][]string, map[string]map[string]string, error) {
+	deps := copyDeps(dtb.deps)
+	if len(dtb.patterns) == 0 {
+		return deps, nil, nil
+	}
+	ids := sortedKeys(dtb.deps)
+	via := make(map[string]map[string]string)
+	for _, node := range sortedKeys(dtb.patterns) {
+		for _, pattern := range dtb.patterns[node] {
+			matched := false
+			for _, id := range ids {
+				ok, err := path.Match(pattern, id)
+				if err != nil {
+					return nil, nil, fmt.Errorf("%w: invalid pattern \"%s\" of \"%s\"", ErrIntegrity, pattern, node)
+				}
+				if !ok || id == node {
+					continue
+				}
+				matched = true
+				if contains(deps[node], id) {
+					continue
+				}
+				deps[node] = append(deps[node], id)
+				if via[node] == nil {
+					via[node] = make(map[string]string)
+				}
+				via[node][id] = pattern
+			}
+			if !matched && !dtb.allowEmptyPatterns {
+				return nil, nil, fmt.Errorf("%w: pattern \"%s\" of \"%s\" matches no nodes", ErrIntegrity, pattern, node)
+			}
+		}
+	}
+	return deps, via, nil
+}
+
+// Edges returns all dependencies of the tree sorted by the nodes and the dependencies. The dependencies expanded from
+// the patterns have the pattern set in Via.
+func (dt *DepTree) Edges() []Edge {
+	result := make([]Edge, 0)
+	for node, deps := range dt.deps {
+		for _, dep := range deps {
+			result = append(result, Edge{From: node, To: dep, Via: dt.via[node][dep]})
+		}
+	}
+	sortEdges(result)
+	return result
+}
+
+// withoutVia returns the patterns of the expanded dependencies restricted to the given dependencies.
+func withoutVia(via map[string]string, deps []string) map[string]string {
+	result := make(map[string]string)
+	for _, dep := range deps {
+		if pattern, ok := via[dep]; ok {
+			result[dep] = pattern
+		}
+	}
+	return result
+}
+
+// chainString joins the chain of nodes with arrows showing the patterns the dependencies come from.
+func chainString(chain []string, via map[string]map[string]string) string {
+	sb := strings.Builder{}
+	for i, node := range chain {
+		if i > 0 {
+			sb.WriteString(arrow(via[chain[i-1]][node]))
+		}
+		sb.WriteString(node)
+	}
+	return sb.String()
+}
+
+// arrow returns "->" or, for a dependency expanded from the pattern, "-[pattern]->".
+func arrow(pattern string) string {
+	if pattern == "" {
+		return "->"
+	}
+	return "-[" + pattern + "]->"
+}
@@ -0,0 +1,153 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type patternNode struct {
+	testNode
+	patterns []string
+}
+
+func (pn *patternNode) PatternDeps() []string {
+	return pn.patterns
+}
+
+func TestDepTreeBuilder_AddPatternDeps(t *testing.T) {
+	tests := []struct {
+		name      string
+		prepare   func(b *DepTreeBuilder)
+		top       string
+		want      []string
+		wantErr   error
+		errSubstr string
+	}{
+		{
+			name: "expanded",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("plugin", "storage.*")
+				b.AddDeps("storage.s3", "core")
+				b.AddDeps("storage.disk")
+				b.AddDeps("core")
+			},
+			top:  "plugin",
+			want: []string{"core", "storage.s3", "storage.disk", "plugin"},
+		},
+		{
+			name: "node itself is not matched",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("storage.all", "storage.*")
+				b.AddDeps("storage.s3")
+			},
+			top:  "storage.all",
+			want: []string{"storage.s3", "storage.all"},
+		},
+		{
+			name: "empty pattern",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("plugin", "storage.*")
+			},
+			wantErr:   ErrIntegrity,
+			errSubstr: `pattern "storage.*" of "plugin" matches no nodes`,
+		},
+		{
+			name: "empty pattern allowed",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("plugin", "storage.*")
+				b.AllowEmptyPatterns()
+			},
+			top:  "plugin",
+			want: []string{"plugin"},
+		},
+		{
+			name: "invalid pattern",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("plugin", "[a")
+			},
+			wantErr:   ErrIntegrity,
+			errSubstr: `invalid pattern "[a"`,
+		},
+		{
+			name: "cycle through pattern",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("plugin", "storage.*")
+				b.AddPatternDeps("storage.s3", "plug*")
+			},
+			wantErr:   ErrIntegrity,
+			errSubstr: "]->",
+		},
+		{
+			name: "replaced",
+			prepare: func(b *DepTreeBuilder) {
+				b.AddPatternDeps("plugin", "storage.*")
+				b.ReplaceDeps("plugin")
+			},
+			top:  "plugin",
+			want: []string{"plugin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewDepTreeBuilder()
+			tt.prepare(builder)
+			tree, err := builder.Build()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Errorf("Build() error = %v, want %v containing %q", err, tt.wantErr, tt.errSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := tree.ListAsc(tt.top); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListAsc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepTree_Edges(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("plugin", "storage.s3")
+	builder.AddPatternDeps("plugin", "storage.*")
+	builder.AddDeps("storage.s3")
+	builder.AddDeps("storage.disk")
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Edge{
+		{From: "plugin", To: "storage.disk", Via: "storage.*"},
+		{From: "plugin", To: "storage.s3"},
+	}
+	if got := tree.Edges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Edges() = %v, want %v", got, want)
+	}
+	if got := want[0].String(); got != "plugin-[storage.*]->storage.disk" {
+		t.Errorf("String() = %v, want %v", got, "plugin-[storage.*]->storage.disk")
+	}
+	sub := tree.Subtree("plugin")
+	if got := sub.Edges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Subtree().Edges() = %v, want %v", got, want)
+	}
+}
+
+func TestNDepTreeBuilder_PatternDeper(t *testing.T) {
+	plugin := &patternNode{testNode: testNode{nodeId: "plugin"}, patterns: []string{"storage.*"}}
+	s3 := &patternNode{testNode: testNode{nodeId: "storage.s3"}}
+	builder := NewNDepTreeBuilder[*patternNode]()
+	builder.AddNode(plugin)
+	builder.AddNode(s3)
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*patternNode{s3, plugin}
+	if got := tree.ListAsc(plugin); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package deptree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PersistentDepTree is an immutable dependency tree. With and Without return a new tree sharing the unchanged
+// structure with the old one, so many variants of a tree may be kept without copying all dependencies. Only the
+// dependencies are kept, conflicts, costs and other metadata of a DepTree are dropped. The tree is safe for concurrent
+// use.
+type PersistentDepTree struct {
+	deps       trie
+	dependents trie
+}
+
+// NewPersistentDepTree returns an empty persistent dependency tree.
+func NewPersistentDepTree() *PersistentDepTree {
+	return &PersistentDepTree{}
+}
+
+// Persistent returns a persistent dependency tree with the dependencies of the tree.
+func (dt *DepTree) Persistent() *PersistentDepTree {
+	pt := &PersistentDepTree{}
+	dependents := make(map[string][]string)
+	for _, node := range sortedKeys(dt.deps) {
+		deps := make([]string, 0, len(dt.deps[node]))
+		for _, dep := range dt.deps[node] {
+			if _, ok := dt.deps[dep]; ok && !contains(deps, dep) {
+				deps = append(deps, dep)
+				dependents[dep] = append(dependents[dep], node)
+			}
+		}
+		pt.deps = pt.deps.set(node, deps)
+	}
+	for node, ds := range dependents {
+		pt.dependents = pt.dependents.set(node, ds)
+	}
+	return pt
+}
+
+// With returns a new tree where the node has the given dependencies. The node is added if it doesn't exist, otherwise
+// its dependencies are replaced. Error is returned if a dependency doesn't exist or the dependencies create a cycle.
+func (pt *PersistentDepTree) With(node string, deps ...string) (*PersistentDepTree, error) {
+	newDeps := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		if _, ok := pt.deps.get(dep); !ok && dep != node {
+			return nil, fmt.Errorf("%w: missing dependency \"%s\"", ErrIntegrity, dep)
+		}
+		if pt.reaches(dep, node) {
+			return nil, fmt.Errorf("%w: cycle detected: %s->%s", ErrIntegrity, node, dep)
+		}
+		if !contains(newDeps, dep) {
+			newDeps = append(newDeps, dep)
+		}
+	}
+	oldDeps, _ := pt.deps.get(node)
+	result := &PersistentDepTree{deps: pt.deps.set(node, newDeps), dependents: pt.dependents}
+	for _, dep := range oldDeps {
+		if !contains(newDeps, dep) {
+			result.dependents = result.updateDependents(dep, node, false)
+		}
+	}
+	for _, dep := range newDeps {
+		if !contains(oldDeps, dep) {
+			result.dependents = result.updateDependents(dep, node, true)
+		}
+	}
+	return result, nil
+}
+
+// Without returns a new tree without the node. Error is returned if the node doesn't exist or other nodes still
+// depend on it.
+func (pt *PersistentDepTree) Without(node string) (*PersistentDepTree, error) {
+	deps, ok := pt.deps.get(node)
+	if !ok {
+		return nil, fmt.Errorf("%w: node \"%s\"", ErrNotFound, node)
+	}
+	if dependents, _ := pt.dependents.get(node); len(dependents) > 0 {
+		return nil, fmt.Errorf("%w: node \"%s\" is required by \"%s\"", ErrIntegrity, node,
+			strings.Join(dependents, "\", \""))
+	}
+	result := &PersistentDepTree{deps: pt.deps.delete(node), dependents: pt.dependents.delete(node)}
+	for _, dep := range deps {
+		result.dependents = result.updateDependents(dep, node, false)
+	}
+	return result, nil
+}
+
+// Len returns the number of nodes in the tree.
+func (pt *PersistentDepTree) Len() int {
+	return pt.deps.size
+}
+
+// ListAsc sorts the dependencies based on provided top nodes. See DepTree.ListAsc for more details.
+func (pt *PersistentDepTree) ListAsc(top ...string) []string {
+	return postorder(pt.deps.get, top)
+}
+
+// ListDesc sorts the dependencies based on provided top nodes. See DepTree.ListDesc for more details.
+func (pt *PersistentDepTree) ListDesc(top ...string) []string {
+	return reversed(pt.ListAsc(top...))
+}
+
+// Tree returns a regular dependency tree with the dependencies of the persistent tree.
+func (pt *PersistentDepTree) Tree() *DepTree {
+	deps := make(map[string][]string, pt.deps.size)
+	pt.deps.each(func(node string, ds []string) {
+		deps[node] = append(make([]string, 0, len(ds)), ds...)
+	})
+	return &DepTree{deps: deps}
+}
+
+// reaches checks whether the target is the node or is required by the node.
+func (pt *PersistentDepTree) reaches(node, target string) bool {
+	visited := map[string]bool{node: true}
+	stack := []string{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == target {
+			return true
+		}
+		deps, _ := pt.deps.get(current)
+		for _, dep := range deps {
+			if !visited[dep] {
+				visited[dep] = true
+				stack = append(stack, dep)
+			}
+		}
+	}
+	return false
+}
+
+// updateDependents returns the dependents with the node added to or removed from the dependents of the dependency.
+func (pt *PersistentDepTree) updateDependents(dep, node string, add bool) trie {
+	old, _ := pt.dependents.get(dep)
+	dependents := make([]string, 0, len(old)+1)
+	for _, d := range old {
+		if d != node {
+			dependents = append(dependents, d)
+		}
+	}
+	if add {
+		dependents = append(dependents, node)
+		sort.Strings(dependents)
+	}
+	if len(dependents) == 0 {
+		return pt.dependents.delete(dep)
+	}
+	return pt.dependents.set(dep, dependents)
+}
@@ -0,0 +1,66 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPersistentDepTree(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("a", "b", "c")
+	builder.AddDeps("b", "c")
+	builder.ForceIntegrity()
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := tree.Persistent()
+	if got, want := base.ListAsc("a"), tree.ListAsc("a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAsc() = %v, want %v", got, want)
+	}
+	withD, err := base.With("d", "a")
+	if err != nil {
+		t.Fatalf("With() error = %v", err)
+	}
+	replaced, err := withD.With("b")
+	if err != nil {
+		t.Fatalf("With() error = %v", err)
+	}
+	if got, want := replaced.ListDesc("d"), []string{"d", "a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDesc() = %v, want %v", got, want)
+	}
+	if got, want := withD.ListDesc("d"), []string{"d", "a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDesc() = %v, want %v", got, want)
+	}
+	if _, err := replaced.With("c", "d"); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("With() error = %v, want %v", err, ErrIntegrity)
+	}
+	if _, err := replaced.With("e", "x"); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("With() error = %v, want %v", err, ErrIntegrity)
+	}
+	if _, err := replaced.Without("a"); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("Without() error = %v, want %v", err, ErrIntegrity)
+	}
+	if _, err := replaced.Without("x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Without() error = %v, want %v", err, ErrNotFound)
+	}
+	withoutD, err := replaced.Without("d")
+	if err != nil {
+		t.Fatalf("Without() error = %v", err)
+	}
+	withoutA, err := withoutD.Without("a")
+	if err != nil {
+		t.Fatalf("Without() error = %v", err)
+	}
+	if withoutA.Len() != 2 || base.Len() != 3 || withD.Len() != 4 {
+		t.Errorf("Len() = %d, %d, %d, want 2, 3, 4", withoutA.Len(), base.Len(), withD.Len())
+	}
+	want := map[string][]string{"b": {}, "c": {}}
+	if got := withoutA.Tree().deps; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tree() = %v, want %v", got, want)
+	}
+	if _, err := withoutA.Without("c"); err != nil {
+		t.Errorf("Without() error = %v", err)
+	}
+}
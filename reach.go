@@ -0,0 +1,81 @@
+package deptree
+
+// IndexReachability makes Build precompute the reachability index of the tree. The index takes O(V·E/64) time and
+// O(V²/64) memory to build, then DependsOn answers in O(1).
+func (dtb *DepTreeBuilder) IndexReachability() {
+	dtb.indexed = true
+}
+
+// DependsOn checks whether node a depends on node b, directly or transitively. False is returned if any of the nodes
+// doesn't exist. If the tree was built with IndexReachability, the check takes constant time.
+func (dt *DepTree) DependsOn(a, b string) bool {
+	if dt.index != nil {
+		return dt.index.dependsOn(a, b)
+	}
+	if _, ok := dt.deps[b]; !ok {
+		return false
+	}
+	return reaches(dt.deps, a, b)
+}
+
+// reaches checks whether b is reachable from the dependencies of a. The search is a single depth first traversal which
+// stops as soon as b is found.
+func reaches[K comparable](deps map[K][]K, a, b K) bool {
+	visited := make(map[K]bool)
+	stack := append([]K{}, deps[a]...)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == b {
+			return true
+		}
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+		stack = append(stack, deps[node]...)
+	}
+	return false
+}
+
+// reachIndex keeps for every interned node a bitset of the nodes it depends on.
+type reachIndex struct {
+	ids  map[string]int
+	deps [][]uint64
+}
+
+func newReachIndex(deps map[string][]string) *reachIndex {
+	ri := &reachIndex{ids: make(map[string]int, len(deps)), deps: make([][]uint64, len(deps))}
+	order := (&DepTree{deps: deps}).ListAsc(sortedKeys(deps)...)
+	for i, node := range order {
+		ri.ids[node] = i
+	}
+	words := (len(order) + 63) / 64
+	for i, node := range order {
+		set := make([]uint64, words)
+		for _, dep := range deps[node] {
+			j, ok := ri.ids[dep]
+			if !ok {
+				continue
+			}
+			set[j/64] |= 1 << (j % 64)
+			for w, word := range ri.deps[j] {
+				set[w] |= word
+			}
+		}
+		ri.deps[i] = set
+	}
+	return ri
+}
+
+func (ri *reachIndex) dependsOn(a, b string) bool {
+	i, ok := ri.ids[a]
+	if !ok {
+		return false
+	}
+	j, ok := ri.ids[b]
+	if !ok {
+		return false
+	}
+	return ri.deps[i][j/64]&(1<<(j%64)) != 0
+}
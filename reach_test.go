@@ -0,0 +1,91 @@
+package deptree
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDepTree_DependsOn(t *testing.T) {
+	deps := map[string][]string{"a": {"b"}, "b": {"c", "e"}, "c": {"d"}, "d": {}, "e": {}, "f": {"x"}}
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "direct", a: "a", b: "b", want: true},
+		{name: "transitive", a: "a", b: "d", want: true},
+		{name: "reversed", a: "d", b: "a", want: false},
+		{name: "siblings", a: "c", b: "e", want: false},
+		{name: "itself", a: "a", b: "a", want: false},
+		{name: "not existent", a: "f", b: "x", want: false},
+	}
+	indexed := &DepTree{deps: deps, index: newReachIndex(deps)}
+	plain := &DepTree{deps: deps}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plain.DependsOn(tt.a, tt.b); got != tt.want {
+				t.Errorf("DependsOn() = %v, want %v", got, tt.want)
+			}
+			if got := indexed.DependsOn(tt.a, tt.b); got != tt.want {
+				t.Errorf("DependsOn() with index = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaches(t *testing.T) {
+	deps := map[string][]string{"a": {"b", "c"}, "b": {"c", "a"}, "c": {"b"}, "d": {"a"}}
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "a", b: "a", want: true},
+		{a: "c", b: "a", want: true},
+		{a: "c", b: "c", want: true},
+		{a: "a", b: "d", want: false},
+		{a: "d", b: "c", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := reaches(deps, tt.a, tt.b); got != tt.want {
+				t.Errorf("reaches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepTreeBuilder_IndexReachability(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	builder := NewDepTreeBuilder()
+	for i := 0; i < 200; i++ {
+		deps := make([]string, 0)
+		for j := 0; j < i; j++ {
+			if rng.Intn(40) == 0 {
+				deps = append(deps, fmt.Sprint(j))
+			}
+		}
+		builder.AddDeps(fmt.Sprint(i), deps...)
+	}
+	plain, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	builder.IndexReachability()
+	indexed, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		for j := 0; j < 200; j++ {
+			a, b := fmt.Sprint(i), fmt.Sprint(j)
+			if plain.DependsOn(a, b) != indexed.DependsOn(a, b) {
+				t.Fatalf("DependsOn(%s, %s) = %v with index", a, b, indexed.DependsOn(a, b))
+			}
+		}
+	}
+	if got, want := postorder(plain.lookup, []string{"199", "150"}), recursiveListAsc(plain.deps, "199", "150"); !reflect.DeepEqual(got, want) {
+		t.Errorf("postorder() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package deptree
+
+// TransitiveReduction returns a new dependency tree with the minimal set of dependencies giving the same
+// reachability. A dependency is dropped if it is required by another dependency of the node, directly or
+// transitively. Duplicated dependencies are dropped as well. If the tree has cycles condensed by CondenseCycles,
+// the dependencies inside the groups are kept and of the dependencies between two groups at most one is kept.
+func (dt *DepTree) TransitiveReduction() *DepTree {
+	if len(dt.groups) > 0 {
+		return dt.derive(dt.condensedReduction())
+	}
+	deps := make(map[string][]string, len(dt.deps))
+	for node := range dt.deps {
+		redundant := redundantDeps(dt.deps, node)
+		deps[node] = make([]string, 0, len(dt.deps[node])-len(redundant))
+		for _, dep := range dt.deps[node] {
+			if !redundant[dep] && !contains(deps[node], dep) {
+				deps[node] = append(deps[node], dep)
+			}
+		}
+	}
+	return dt.derive(deps)
+}
+
+// condensedReduction returns the dependencies of the tree with cycles reduced on the condensed graph. The first
+// dependency between two groups in the order of the ids is kept if the dependency of the groups isn't redundant.
+func (dt *DepTree) condensedReduction() map[string][]string {
+	lookup := dt.groupLookup(dt.lookup)
+	condensed := make(map[string][]string)
+	for node := range dt.deps {
+		rep := dt.representative(node)
+		if _, ok := condensed[rep]; !ok {
+			condensed[rep], _ = lookup(rep)
+		}
+	}
+	redundant := make(map[string]map[string]bool, len(condensed))
+	for rep := range condensed {
+		redundant[rep] = redundantDeps(condensed, rep)
+	}
+	connected := make(map[Edge]bool)
+	deps := make(map[string][]string, len(dt.deps))
+	for _, node := range sortedKeys(dt.deps) {
+		from := dt.representative(node)
+		deps[node] = make([]string, 0, len(dt.deps[node]))
+		for _, dep := range dt.deps[node] {
+			to := dt.representative(dep)
+			groups := Edge{From: from, To: to}
+			switch {
+			case contains(deps[node], dep):
+			case from == to:
+				deps[node] = append(deps[node], dep)
+			case !redundant[from][to] && !connected[groups]:
+				connected[groups] = true
+				deps[node] = append(deps[node], dep)
+			}
+		}
+	}
+	return deps
+}
+
+// RedundantDeps lists the declared dependencies which are required by another dependency of the same node anyway,
+// directly or transitively, and duplicated dependencies. The result maps the node to its redundant dependencies in the
+// declared order. Nodes without redundant dependencies are omitted. The builder doesn't have to be buildable.
+func (dtb *DepTreeBuilder) RedundantDeps() map[string][]string {
+	result := make(map[string][]string)
+	for node, deps := range dtb.deps {
+		redundant := redundantDeps(dtb.deps, node)
+		seen := make(map[string]bool)
+		for _, dep := range deps {
+			if (redundant[dep] || seen[dep]) && !contains(result[node], dep) {
+				result[node] = append(result[node], dep)
+			}
+			seen[dep] = true
+		}
+	}
+	return result
+}
+
+// redundantDeps returns the direct dependencies of the node reachable through another direct dependency.
+func redundantDeps(deps map[string][]string, node string) map[string]bool {
+	redundant := make(map[string]bool)
+	direct := make(map[string]bool)
+	for _, dep := range deps[node] {
+		direct[dep] = true
+	}
+	for dep := range direct {
+		for _, next := range deps[dep] {
+			if next == node {
+				continue
+			}
+			for reached := range reachable(deps, next) {
+				if direct[reached] && reached != dep {
+					redundant[reached] = true
+				}
+			}
+		}
+	}
+	return redundant
+}
+
+// derive returns a new dependency tree with the given dependencies and the metadata of the tree restricted to
+// the nodes of the dependencies.
+func (dt *DepTree) derive(deps map[string][]string) *DepTree {
+	tree := &DepTree{deps: deps, ordering: dt.ordering}
+	for node, conflicts := range dt.conflicts {
+		if _, ok := deps[node]; ok {
+			if tree.conflicts == nil {
+				tree.conflicts = make(map[string][]string)
+			}
+			tree.conflicts[node] = append([]string{}, conflicts...)
+		}
+	}
+	for node, cost := range dt.costs {
+		if _, ok := deps[node]; ok {
+			if tree.costs == nil {
+				tree.costs = make(map[string]float64)
+			}
+			tree.costs[node] = cost
+		}
+	}
+	for node, priority := range dt.priorities {
+		if _, ok := deps[node]; ok {
+			if tree.priorities == nil {
+				tree.priorities = make(map[string]int)
+			}
+			tree.priorities[node] = priority
+		}
+	}
+	for node, tags := range dt.tags {
+		if _, ok := deps[node]; ok {
+			if tree.tags == nil {
+				tree.tags = make(map[string][]string)
+			}
+			tree.tags[node] = append([]string{}, tags...)
+		}
+	}
+	for node, typed := range dt.typed {
+		if ds, ok := deps[node]; ok {
+			if tree.typed == nil {
+				tree.typed = make(map[string][]TypedDep)
+			}
+			tree.typed[node] = withoutTypedDeps(typed, ds)
+		}
+	}
+	for node, via := range dt.via {
+		if ds, ok := deps[node]; ok {
+			if tree.via == nil {
+				tree.via = make(map[string]map[string]string)
+			}
+			tree.via[node] = withoutVia(via, ds)
+		}
+	}
+	if dt.condensed {
+		tree.condense()
+	}
+	if dt.index != nil && len(tree.groups) == 0 {
+		tree.index = newReachIndex(deps)
+	}
+	return tree
+}
@@ -0,0 +1,59 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDepTree_TransitiveReduction(t *testing.T) {
+	tests := []struct {
+		name string
+		deps map[string][]string
+		want map[string][]string
+	}{
+		{
+			name: "redundant edge",
+			deps: map[string][]string{"a": {"b", "c"}, "b": {"c"}, "c": {}},
+			want: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {}},
+		},
+		{
+			name: "transitive redundant edge",
+			deps: map[string][]string{"a": {"d", "b"}, "b": {"c"}, "c": {"d"}, "d": {}},
+			want: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"d"}, "d": {}},
+		},
+		{
+			name: "duplicated edge",
+			deps: map[string][]string{"a": {"b", "b"}, "b": {}},
+			want: map[string][]string{"a": {"b"}, "b": {}},
+		},
+		{
+			name: "minimal tree",
+			deps: map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": {}},
+			want: map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := &DepTree{deps: tt.deps, costs: map[string]float64{"a": 2}}
+			got := dt.TransitiveReduction()
+			if !reflect.DeepEqual(got.deps, tt.want) {
+				t.Errorf("TransitiveReduction() = %v, want %v", got.deps, tt.want)
+			}
+			if !reflect.DeepEqual(got.costs, dt.costs) {
+				t.Errorf("TransitiveReduction() costs = %v, want %v", got.costs, dt.costs)
+			}
+		})
+	}
+}
+
+func TestDepTreeBuilder_RedundantDeps(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("a", "b", "c", "d", "c")
+	builder.AddDeps("b", "c")
+	builder.AddDeps("c", "d")
+	builder.AddDeps("e", "f")
+	want := map[string][]string{"a": {"c", "d"}}
+	if got := builder.RedundantDeps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedundantDeps() = %v, want %v", got, want)
+	}
+}
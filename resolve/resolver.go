@@ -0,0 +1,213 @@
+// Package resolve picks a consistent set of package versions and hands it to a deptree builder.
+package resolve
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MaciejPuczkowski/deptree"
+)
+
+var ErrUnsatisfiable = fmt.Errorf("unsatisfiable requirements")
+
+// Strategy decides which of the compatible versions is preferred.
+type Strategy int
+
+const (
+	// Newest prefers the newest version compatible with all requirements.
+	Newest Strategy = iota
+	// Minimal prefers the oldest version compatible with all requirements. It's not the minimal version selection
+	// of Go modules, which picks the highest of the required minimums.
+	Minimal
+)
+
+type release struct {
+	version  Version
+	requires []Requirement
+}
+
+// Resolver collects the available versions of the packages and resolves requirements to a consistent version set.
+type Resolver struct {
+	strategy Strategy
+	packages map[string][]release
+}
+
+// NewResolver returns a new resolver using the given strategy.
+func NewResolver(strategy Strategy) *Resolver {
+	return &Resolver{
+		strategy: strategy,
+		packages: make(map[string][]release),
+	}
+}
+
+// AddVersion adds an available version of the package. The requirements are strings like "auth>=1.2,<2".
+// Error is returned if the version or any of the requirements can't be parsed or the version is already added.
+func (r *Resolver) AddVersion(id, version string, requires ...string) error {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return err
+	}
+	rel := release{version: v, requires: make([]Requirement, len(requires))}
+	for i, s := range requires {
+		if rel.requires[i], err = ParseRequirement(s); err != nil {
+			return err
+		}
+	}
+	for _, existing := range r.packages[id] {
+		if existing.version.Compare(v) == 0 {
+			return fmt.Errorf("%w: version %s of \"%s\" is already added", ErrVersion, v, id)
+		}
+	}
+	r.packages[id] = append(r.packages[id], rel)
+	return nil
+}
+
+// Resolve picks a version for every package required by the given requirements, directly or transitively, so that
+// all requirements are satisfied. When more versions are compatible, the strategy of the resolver decides.
+// ErrUnsatisfiable is returned if there is no consistent version set.
+func (r *Resolver) Resolve(requires ...string) (*Resolution, error) {
+	s := &solver{
+		resolver:    r,
+		selected:    make(map[string]release),
+		constraints: make(map[string][]Requirement),
+	}
+	pending := make([]string, 0)
+	for _, req := range requires {
+		parsed, err := ParseRequirement(req)
+		if err != nil {
+			return nil, err
+		}
+		s.constraints[parsed.Id] = append(s.constraints[parsed.Id], parsed)
+		pending = append(pending, parsed.Id)
+	}
+	if !s.solve(pending) {
+		return nil, s.failure
+	}
+	res := &Resolution{
+		Versions: make(map[string]Version),
+		deps:     make(map[string][]string),
+	}
+	for id, rel := range s.selected {
+		res.Versions[id] = rel.version
+		res.deps[id] = make([]string, len(rel.requires))
+		for i, req := range rel.requires {
+			res.deps[id][i] = req.Id
+		}
+	}
+	return res, nil
+}
+
+type solver struct {
+	resolver    *Resolver
+	selected    map[string]release
+	constraints map[string][]Requirement
+	failure     error
+}
+
+func (s *solver) solve(pending []string) bool {
+	for len(pending) > 0 {
+		if _, ok := s.selected[pending[0]]; !ok {
+			break
+		}
+		pending = pending[1:]
+	}
+	if len(pending) == 0 {
+		return true
+	}
+	id := pending[0]
+	for _, candidate := range s.candidates(id) {
+		if !s.compatible(candidate) {
+			continue
+		}
+		s.selected[id] = candidate
+		next := append([]string{}, pending[1:]...)
+		for _, req := range candidate.requires {
+			s.constraints[req.Id] = append(s.constraints[req.Id], req)
+			next = append(next, req.Id)
+		}
+		if s.solve(next) {
+			return true
+		}
+		for _, req := range candidate.requires {
+			s.constraints[req.Id] = s.constraints[req.Id][:len(s.constraints[req.Id])-1]
+		}
+		delete(s.selected, id)
+	}
+	if s.failure == nil {
+		s.failure = s.unsatisfied(id)
+	}
+	return false
+}
+
+// candidates returns the versions of the package satisfying the current constraints in the order of preference.
+func (s *solver) candidates(id string) []release {
+	result := make([]release, 0)
+	for _, rel := range s.resolver.packages[id] {
+		ok := true
+		for _, req := range s.constraints[id] {
+			ok = ok && req.Allows(rel.version)
+		}
+		if ok {
+			result = append(result, rel)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if s.resolver.strategy == Minimal {
+			return result[i].version.Compare(result[j].version) < 0
+		}
+		return result[i].version.Compare(result[j].version) > 0
+	})
+	return result
+}
+
+// compatible checks whether the requirements of the candidate are satisfied by the already selected versions.
+func (s *solver) compatible(candidate release) bool {
+	for _, req := range candidate.requires {
+		if sel, ok := s.selected[req.Id]; ok && !req.Allows(sel.version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *solver) unsatisfied(id string) error {
+	if _, ok := s.resolver.packages[id]; !ok {
+		return fmt.Errorf("%w: unknown package \"%s\"", ErrUnsatisfiable, id)
+	}
+	reqs := make([]string, len(s.constraints[id]))
+	for i, req := range s.constraints[id] {
+		reqs[i] = req.String()
+	}
+	return fmt.Errorf("%w: no version of \"%s\" satisfies %s", ErrUnsatisfiable, id, strings.Join(reqs, " and "))
+}
+
+// Resolution is a consistent version set returned by the Resolver.
+type Resolution struct {
+	Versions map[string]Version
+	deps     map[string][]string
+}
+
+// Builder returns a dependency tree builder with the resolved packages as the nodes. Node ids are the package ids,
+// the chosen versions are available in Versions.
+func (r *Resolution) Builder() *deptree.DepTreeBuilder {
+	builder := deptree.NewDepTreeBuilder()
+	for _, id := range r.ids() {
+		builder.AddDeps(id, r.deps[id]...)
+	}
+	return builder
+}
+
+// Build builds a dependency tree from the resolved packages. See Builder for more details.
+func (r *Resolution) Build() (*deptree.DepTree, error) {
+	return r.Builder().Build()
+}
+
+func (r *Resolution) ids() []string {
+	ids := make([]string, 0, len(r.deps))
+	for id := range r.deps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,109 @@
+package resolve
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestResolver(t *testing.T, strategy Strategy) *Resolver {
+	r := NewResolver(strategy)
+	versions := []struct {
+		id       string
+		version  string
+		requires []string
+	}{
+		{id: "app", version: "1.0.0", requires: []string{"auth>=1.2,<2", "log"}},
+		{id: "auth", version: "1.1.0", requires: []string{"log"}},
+		{id: "auth", version: "1.2.0", requires: []string{"log>=1"}},
+		{id: "auth", version: "1.3.0", requires: []string{"log>=2"}},
+		{id: "auth", version: "2.0.0", requires: []string{"log>=2"}},
+		{id: "log", version: "0.9.0"},
+		{id: "log", version: "1.0.0"},
+		{id: "log", version: "1.5.0"},
+		{id: "log", version: "2.1.0"},
+	}
+	for _, v := range versions {
+		if err := r.AddVersion(v.id, v.version, v.requires...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return r
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		requires []string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "newest",
+			strategy: Newest,
+			requires: []string{"app"},
+			want:     map[string]string{"app": "1.0.0", "auth": "1.3.0", "log": "2.1.0"},
+		},
+		{
+			name:     "minimal",
+			strategy: Minimal,
+			requires: []string{"app"},
+			want:     map[string]string{"app": "1.0.0", "auth": "1.2.0", "log": "1.0.0"},
+		},
+		{
+			name:     "backtracking",
+			strategy: Newest,
+			requires: []string{"app", "log<2"},
+			want:     map[string]string{"app": "1.0.0", "auth": "1.2.0", "log": "1.5.0"},
+		},
+		{
+			name:     "unsatisfiable",
+			strategy: Newest,
+			requires: []string{"app", "log<1"},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown package",
+			strategy: Newest,
+			requires: []string{"db"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTestResolver(t, tt.strategy).Resolve(tt.requires...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsatisfiable) {
+					t.Errorf("Resolve() error = %v, want %v", err, ErrUnsatisfiable)
+				}
+				return
+			}
+			versions := make(map[string]string)
+			for id, v := range got.Versions {
+				versions[id] = v.String()
+			}
+			if !reflect.DeepEqual(versions, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolution_Build(t *testing.T) {
+	res, err := newTestResolver(t, Newest).Resolve("app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tree, err := res.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"log", "auth", "app"}
+	if actual := tree.ListAsc("app"); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %v, actual: %v", expected, actual)
+	}
+}
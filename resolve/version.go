@@ -0,0 +1,191 @@
+package resolve
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrVersion = fmt.Errorf("version error")
+
+// Version is a semantic version. Missing minor or patch numbers are assumed to be zero, so "1.2" equals "1.2.0".
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// ParseVersion parses a version like "1.2.3", "v1.2" or "2.0.0-rc.1".
+func ParseVersion(s string) (Version, error) {
+	v := Version{}
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(raw, '+'); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.IndexByte(raw, '-'); i >= 0 {
+		v.Prerelease = raw[i+1:]
+		raw = raw[:i]
+	}
+	parts := strings.Split(raw, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("%w: invalid version \"%s\"", ErrVersion, s)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("%w: invalid version \"%s\"", ErrVersion, s)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if the version is lower, equal or greater than the other version. A prerelease version
+// is lower than the release version. Prereleases are compared by the dot separated identifiers like in semver, so
+// "1.0.0-rc.2" is lower than "1.0.0-rc.10".
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, "."))
+}
+
+// comparePrerelease compares the prerelease identifiers. Numeric identifiers are compared as numbers and they are
+// lower than the alphanumeric ones. A shorter prerelease is lower if all its identifiers are equal to the other ones.
+func comparePrerelease(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.ParseUint(a[i], 10, 64)
+		nb, errB := strconv.ParseUint(b[i], 10, 64)
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			if na < nb {
+				return -1
+			}
+			return 1
+		case errA == nil && errB != nil:
+			return -1
+		case errA != nil && errB == nil:
+			return 1
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Constraint is a single comparison of a version, like ">=1.2".
+type Constraint struct {
+	Op      string
+	Version Version
+}
+
+// Allows checks whether the version satisfies the constraint.
+func (c Constraint) Allows(v Version) bool {
+	cmp := v.Compare(c.Version)
+	switch c.Op {
+	case "=", "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func (c Constraint) String() string {
+	return c.Op + c.Version.String()
+}
+
+// Requirement is a package id with the constraints its version must satisfy, like "auth>=1.2,<2".
+// A requirement without constraints accepts any version.
+type Requirement struct {
+	Id          string
+	Constraints []Constraint
+}
+
+// ParseRequirement parses a requirement like "auth>=1.2,<2". The supported operators are =, ==, !=, >, >=, < and <=.
+func ParseRequirement(s string) (Requirement, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexAny(s, "<>=!")
+	if i < 0 {
+		if s == "" {
+			return Requirement{}, fmt.Errorf("%w: empty requirement", ErrVersion)
+		}
+		return Requirement{Id: s}, nil
+	}
+	r := Requirement{Id: strings.TrimSpace(s[:i])}
+	if r.Id == "" {
+		return Requirement{}, fmt.Errorf("%w: missing package id in \"%s\"", ErrVersion, s)
+	}
+	for _, part := range strings.Split(s[i:], ",") {
+		part = strings.TrimSpace(part)
+		op := part[:len(part)-len(strings.TrimLeft(part, "<>=!"))]
+		switch op {
+		case "=", "==", "!=", ">", ">=", "<", "<=":
+		default:
+			return Requirement{}, fmt.Errorf("%w: invalid constraint \"%s\" in \"%s\"", ErrVersion, part, s)
+		}
+		v, err := ParseVersion(part[len(op):])
+		if err != nil {
+			return Requirement{}, err
+		}
+		r.Constraints = append(r.Constraints, Constraint{Op: op, Version: v})
+	}
+	return r, nil
+}
+
+// Allows checks whether the version satisfies all constraints of the requirement.
+func (r Requirement) Allows(v Version) bool {
+	for _, c := range r.Constraints {
+		if !c.Allows(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Requirement) String() string {
+	cs := make([]string, len(r.Constraints))
+	for i, c := range r.Constraints {
+		cs[i] = c.String()
+	}
+	return r.Id + strings.Join(cs, ",")
+}
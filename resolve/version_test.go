@@ -0,0 +1,91 @@
+package resolve
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Version
+		wantErr bool
+	}{
+		{name: "full", s: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{name: "short", s: "v2", want: Version{Major: 2}},
+		{name: "prerelease", s: "1.0.0-rc.1+build", want: Version{Major: 1, Prerelease: "rc.1"}},
+		{name: "invalid", s: "1.x", wantErr: true},
+		{name: "too long", s: "1.2.3.4", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersion(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.3", b: "1.2.3", want: 0},
+		{a: "1.2", b: "1.10", want: -1},
+		{a: "2.0.0", b: "1.9.9", want: 1},
+		{a: "1.0.0-rc.1", b: "1.0.0", want: -1},
+		{a: "1.0.0-beta", b: "1.0.0-alpha", want: 1},
+		{a: "1.0.0-rc.2", b: "1.0.0-rc.10", want: -1},
+		{a: "1.0.0-rc.1", b: "1.0.0-rc.a", want: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-alpha.1", want: -1},
+		{a: "1.0.0-alpha.beta", b: "1.0.0-alpha.1", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, _ := ParseVersion(tt.a)
+			b, _ := ParseVersion(tt.b)
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Requirement
+		wantErr bool
+	}{
+		{name: "no constraints", s: "auth", want: Requirement{Id: "auth"}},
+		{
+			name: "range",
+			s:    "auth>=1.2,<2",
+			want: Requirement{Id: "auth", Constraints: []Constraint{
+				{Op: ">=", Version: Version{Major: 1, Minor: 2}},
+				{Op: "<", Version: Version{Major: 2}},
+			}},
+		},
+		{name: "invalid operator", s: "auth=>1", wantErr: true},
+		{name: "missing id", s: ">=1", wantErr: true},
+		{name: "empty constraint", s: "auth>=1,", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRequirement(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequirement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequirement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package deptree
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var ErrSchedule = fmt.Errorf("schedule error")
+
+// Task is a node assigned to a worker. Start and Finish are computed from the costs of the nodes.
+type Task struct {
+	Id     string  `json:"id"`
+	Worker int     `json:"worker"`
+	Start  float64 `json:"start"`
+	Finish float64 `json:"finish"`
+}
+
+// Schedule is a static plan of execution of the nodes on a fixed number of workers. Tasks are sorted by the start.
+type Schedule struct {
+	Workers  int     `json:"workers"`
+	Makespan float64 `json:"makespan"`
+	Tasks    []Task  `json:"tasks"`
+}
+
+// Schedule assigns the nodes required by the given tops to the workers. The node starts when all its dependencies are
+// finished. Durations of the nodes are the costs (see SetCost and Coster). When more nodes are ready, the node with
+// the heaviest chain of the dependent nodes goes first (list scheduling with critical-path priority). The nodes of
+// a cycle condensed by CondenseCycles are scheduled as a single node, one after another on the same worker in
+// the order of the ids. Error is returned if the number of workers is less than one.
+func (dt *DepTree) Schedule(workers int, top ...string) (*Schedule, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("%w: invalid number of workers %d", ErrSchedule, workers)
+	}
+	order := dt.condensedOrder(top)
+	lookup := dt.condensedLookup()
+	position := make(map[string]int, len(order))
+	for i, node := range order {
+		position[node] = i
+	}
+	dependents := make(map[string][]string)
+	waiting := make(map[string]int)
+	for _, node := range order {
+		deps, _ := lookup(node)
+		for _, dep := range deps {
+			if _, ok := position[dep]; ok {
+				dependents[dep] = append(dependents[dep], node)
+				waiting[node]++
+			}
+		}
+	}
+	priority := make(map[string]float64, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		for _, dependent := range dependents[node] {
+			priority[node] = math.Max(priority[node], priority[dependent])
+		}
+		priority[node] += dt.groupCost(node)
+	}
+	ready := make([]string, 0)
+	for _, node := range order {
+		if waiting[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+	available := make([]float64, workers)
+	finish := make(map[string]float64, len(order))
+	result := &Schedule{Workers: workers, Tasks: make([]Task, 0, len(order))}
+	for len(ready) > 0 {
+		best := 0
+		for i, node := range ready {
+			if priority[node] > priority[ready[best]] ||
+				(priority[node] == priority[ready[best]] && position[node] < position[ready[best]]) {
+				best = i
+			}
+		}
+		node := ready[best]
+		ready = append(ready[:best], ready[best+1:]...)
+		depsFinish := 0.0
+		deps, _ := lookup(node)
+		for _, dep := range deps {
+			if _, ok := position[dep]; ok {
+				depsFinish = math.Max(depsFinish, finish[dep])
+			}
+		}
+		worker := 0
+		for w := range available {
+			if math.Max(available[w], depsFinish) < math.Max(available[worker], depsFinish) {
+				worker = w
+			}
+		}
+		start := math.Max(available[worker], depsFinish)
+		for _, member := range dt.members(node) {
+			task := Task{Id: member, Worker: worker, Start: start, Finish: start + dt.cost(member)}
+			result.insert(task)
+			start = task.Finish
+		}
+		available[worker] = start
+		finish[node] = start
+		result.Makespan = math.Max(result.Makespan, start)
+		for _, dependent := range dependents[node] {
+			waiting[dependent]--
+			if waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *Schedule) insert(task Task) {
+	i := len(s.Tasks)
+	for i > 0 && (s.Tasks[i-1].Start > task.Start ||
+		(s.Tasks[i-1].Start == task.Start && s.Tasks[i-1].Worker > task.Worker)) {
+		i--
+	}
+	s.Tasks = append(s.Tasks, Task{})
+	copy(s.Tasks[i+1:], s.Tasks[i:])
+	s.Tasks[i] = task
+}
+
+// Gantt renders the schedule as a text Gantt chart. Every task is a row, the bars are scaled to the given width and
+// cut to fit in it. Error is returned if the width is less than one.
+func (s *Schedule) Gantt(width int) (string, error) {
+	if width < 1 {
+		return "", fmt.Errorf("%w: invalid width %d", ErrSchedule, width)
+	}
+	idWidth := 0
+	for _, task := range s.Tasks {
+		if len(task.Id) > idWidth {
+			idWidth = len(task.Id)
+		}
+	}
+	sb := strings.Builder{}
+	for _, task := range s.Tasks {
+		from, to := 0, 0
+		if s.Makespan > 0 {
+			from = int(math.Round(task.Start / s.Makespan * float64(width)))
+			to = int(math.Round(task.Finish / s.Makespan * float64(width)))
+		}
+		from = max(0, min(from, width))
+		to = max(from, min(to, width))
+		if to == from && task.Finish > task.Start && to < width {
+			to++
+		}
+		bar := strings.Repeat(" ", from) + strings.Repeat("#", to-from) + strings.Repeat(" ", width-to)
+		sb.WriteString(fmt.Sprintf("%-*s w%d |%s| %g-%g\n", idWidth, task.Id, task.Worker, bar, task.Start, task.Finish))
+	}
+	return sb.String(), nil
+}
+
+// JSON renders the schedule as JSON.
+func (s *Schedule) JSON() ([]byte, error) {
+	return json.Marshal(s)
+}
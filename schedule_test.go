@@ -0,0 +1,102 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDepTree_Schedule(t *testing.T) {
+	deps := map[string][]string{"a": {"b", "c", "d"}, "b": {"e"}, "c": {}, "d": {}, "e": {}}
+	costs := map[string]float64{"a": 1, "b": 2, "c": 3, "d": 1, "e": 4}
+	tests := []struct {
+		name    string
+		workers int
+		want    *Schedule
+		wantErr bool
+	}{
+		{
+			name:    "single worker",
+			workers: 1,
+			want: &Schedule{Workers: 1, Makespan: 11, Tasks: []Task{
+				{Id: "e", Worker: 0, Start: 0, Finish: 4},
+				{Id: "c", Worker: 0, Start: 4, Finish: 7},
+				{Id: "b", Worker: 0, Start: 7, Finish: 9},
+				{Id: "d", Worker: 0, Start: 9, Finish: 10},
+				{Id: "a", Worker: 0, Start: 10, Finish: 11},
+			}},
+		},
+		{
+			name:    "two workers",
+			workers: 2,
+			want: &Schedule{Workers: 2, Makespan: 7, Tasks: []Task{
+				{Id: "e", Worker: 0, Start: 0, Finish: 4},
+				{Id: "c", Worker: 1, Start: 0, Finish: 3},
+				{Id: "d", Worker: 1, Start: 3, Finish: 4},
+				{Id: "b", Worker: 0, Start: 4, Finish: 6},
+				{Id: "a", Worker: 0, Start: 6, Finish: 7},
+			}},
+		},
+		{
+			name:    "no workers",
+			workers: 0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt := &DepTree{deps: deps, costs: costs}
+			got, err := dt.Schedule(tt.workers, "a")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Schedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrSchedule) {
+					t.Errorf("Schedule() error = %v, want %v", err, ErrSchedule)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Render(t *testing.T) {
+	s := &Schedule{Workers: 2, Makespan: 4, Tasks: []Task{
+		{Id: "db", Worker: 0, Start: 0, Finish: 2},
+		{Id: "c", Worker: 1, Start: 0, Finish: 1},
+		{Id: "app", Worker: 0, Start: 2, Finish: 4},
+	}}
+	wantGantt := "db  w0 |####    | 0-2\n" +
+		"c   w1 |##      | 0-1\n" +
+		"app w0 |    ####| 2-4\n"
+	if got, err := s.Gantt(8); err != nil || got != wantGantt {
+		t.Errorf("Gantt() = %q, %v, want %q", got, err, wantGantt)
+	}
+	wantJSON := `{"workers":2,"makespan":4,"tasks":[{"id":"db","worker":0,"start":0,"finish":2},` +
+		`{"id":"c","worker":1,"start":0,"finish":1},{"id":"app","worker":0,"start":2,"finish":4}]}`
+	if got, err := s.JSON(); err != nil || string(got) != wantJSON {
+		t.Errorf("JSON() = %s, %v, want %s", got, err, wantJSON)
+	}
+}
+
+func TestSchedule_GanttBounds(t *testing.T) {
+	s := &Schedule{Workers: 1, Makespan: 2, Tasks: []Task{
+		{Id: "a", Worker: 0, Start: -1, Finish: 1},
+		{Id: "b", Worker: 0, Start: 2, Finish: -1},
+		{Id: "c", Worker: 0, Start: 1, Finish: 3},
+	}}
+	want := "a w0 |##  | -1-1\n" +
+		"b w0 |    | 2--1\n" +
+		"c w0 |  ##| 1-3\n"
+	if got, err := s.Gantt(4); err != nil || got != want {
+		t.Errorf("Gantt() = %q, %v, want %q", got, err, want)
+	}
+	for _, width := range []int{0, -1} {
+		if _, err := s.Gantt(width); !errors.Is(err, ErrSchedule) {
+			t.Errorf("Gantt(%d) error = %v, want %v", width, err, ErrSchedule)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package deptree
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+var ErrSelector = fmt.Errorf("selector error")
+
+// Tagger is an optional interface for a Node. If the node implements it, the builders add the returned tags to the
+// node.
+type Tagger interface {
+	Tags() []string
+}
+
+// AddTags adds tags to the node. The tags are used by the selectors, see Select.
+func (dtb *DepTreeBuilder) AddTags(node string, tags ...string) {
+	if dtb.tags == nil {
+		dtb.tags = make(map[string][]string)
+	}
+	for _, tag := range tags {
+		if !contains(dtb.tags[node], tag) {
+			dtb.tags[node] = append(dtb.tags[node], tag)
+		}
+	}
+}
+
+// Select returns the sorted ids of the nodes matching the selector. The selector is a comma separated list of terms:
+//
+//   - "tag:x" matches the nodes tagged x,
+//   - any other term is a glob pattern matching the node ids, see path.Match,
+//   - "!term" excludes the nodes matching the term.
+//
+// The result is the union of the nodes matching the terms without the excluded nodes. If there are only excluding
+// terms, all nodes but the excluded ones are selected. Error is returned if the selector is malformed.
+func (dt *DepTree) Select(selector string) ([]string, error) {
+	included := make(map[string]bool)
+	excluded := make(map[string]bool)
+	onlyExcluding := true
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		set := included
+		if strings.HasPrefix(term, "!") {
+			set = excluded
+			term = strings.TrimSpace(term[1:])
+		} else {
+			onlyExcluding = false
+		}
+		match, err := dt.matcher(term)
+		if err != nil {
+			return nil, err
+		}
+		for node := range dt.deps {
+			if match(node) {
+				set[node] = true
+			}
+		}
+	}
+	result := make([]string, 0)
+	for _, node := range sortedKeys(dt.deps) {
+		if (included[node] || onlyExcluding) && !excluded[node] {
+			result = append(result, node)
+		}
+	}
+	return result, nil
+}
+
+// ListAscSelect sorts the dependencies of the nodes matching the selector. See Select and ListAsc for more details.
+func (dt *DepTree) ListAscSelect(selector string) ([]string, error) {
+	top, err := dt.Select(selector)
+	if err != nil {
+		return nil, err
+	}
+	return dt.ListAsc(top...), nil
+}
+
+// ListDescSelect sorts the dependencies of the nodes matching the selector. See Select and ListDesc for more details.
+func (dt *DepTree) ListDescSelect(selector string) ([]string, error) {
+	top, err := dt.Select(selector)
+	if err != nil {
+		return nil, err
+	}
+	return dt.ListDesc(top...), nil
+}
+
+func (dt *DepTree) matcher(term string) (func(node string) bool, error) {
+	pattern, isTag := strings.CutPrefix(term, "tag:")
+	if pattern == "" {
+		return nil, fmt.Errorf("%w: empty term", ErrSelector)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w: invalid pattern \"%s\"", ErrSelector, pattern)
+	}
+	if !isTag {
+		return func(node string) bool {
+			ok, _ := path.Match(pattern, node)
+			return ok
+		}, nil
+	}
+	return func(node string) bool {
+		for _, tag := range dt.tags[node] {
+			if ok, _ := path.Match(pattern, tag); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// ListAscSelect sorts the dependencies of the nodes matching the selector. See DepTree.Select for more details.
+func (dt *NDepTree[N]) ListAscSelect(selector string) ([]N, error) {
+	ids, err := dt.tree.ListAscSelect(selector)
+	if err != nil {
+		return nil, err
+	}
+	return dt.list(ids), nil
+}
+
+// ListDescSelect sorts the dependencies of the nodes matching the selector. See DepTree.Select for more details.
+func (dt *NDepTree[N]) ListDescSelect(selector string) ([]N, error) {
+	ids, err := dt.tree.ListDescSelect(selector)
+	if err != nil {
+		return nil, err
+	}
+	return dt.list(ids), nil
+}
+
+// ListAscSelect sorts the dependencies of the nodes matching the selector. See DepTree.Select for more details.
+func (dt *IDepTree) ListAscSelect(selector string) ([]Node, error) {
+	return (*NDepTree[Node])(dt).ListAscSelect(selector)
+}
+
+// ListDescSelect sorts the dependencies of the nodes matching the selector. See DepTree.Select for more details.
+func (dt *IDepTree) ListDescSelect(selector string) ([]Node, error) {
+	return (*NDepTree[Node])(dt).ListDescSelect(selector)
+}
@@ -0,0 +1,93 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type taggedNode struct {
+	testNode
+	tags []string
+}
+
+func (tn *taggedNode) Tags() []string {
+	return tn.tags
+}
+
+func TestDepTree_Select(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("payments.api", "payments.db")
+	builder.AddDeps("payments.legacy", "payments.db")
+	builder.AddDeps("payments.db")
+	builder.AddDeps("users.api", "users.db")
+	builder.AddDeps("users.db")
+	builder.AddTags("payments.api", "payments", "http")
+	builder.AddTags("payments.legacy", "payments")
+	builder.AddTags("users.api", "users", "http")
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		selector string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "tag", selector: "tag:payments", want: []string{"payments.api", "payments.legacy"}},
+		{name: "glob", selector: "*.db", want: []string{"payments.db", "users.db"}},
+		{name: "union", selector: "tag:users, payments.db", want: []string{"payments.db", "users.api"}},
+		{name: "negation", selector: "tag:http,!users.*", want: []string{"payments.api"}},
+		{name: "only negation", selector: "!tag:payments,!*.db", want: []string{"users.api"}},
+		{name: "tag glob", selector: "tag:pay*", want: []string{"payments.api", "payments.legacy"}},
+		{name: "nothing", selector: "tag:missing", want: []string{}},
+		{name: "empty term", selector: "tag:http,", wantErr: true},
+		{name: "empty tag", selector: "tag:", wantErr: true},
+		{name: "invalid glob", selector: "[a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tree.Select(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrSelector) {
+					t.Errorf("Select() error = %v, want %v", err, ErrSelector)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	want := []string{"payments.db", "payments.legacy", "payments.api"}
+	if got, err := tree.ListAscSelect("tag:payments"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAscSelect() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := tree.ListDescSelect(""); !errors.Is(err, ErrSelector) {
+		t.Errorf("ListDescSelect() error = %v, want %v", err, ErrSelector)
+	}
+}
+
+func TestNDepTree_ListDescSelect(t *testing.T) {
+	nodes := []*taggedNode{
+		{testNode: testNode{nodeId: "api", deps: []string{"db"}}, tags: []string{"service"}},
+		{testNode: testNode{nodeId: "db"}},
+		{testNode: testNode{nodeId: "worker"}, tags: []string{"job"}},
+	}
+	builder := NewNDepTreeBuilder[*taggedNode]()
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []*taggedNode{nodes[0], nodes[1]}
+	if got, err := tree.ListDescSelect("tag:service"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDescSelect() = %v, %v, want %v", got, err, want)
+	}
+}
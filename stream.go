@@ -0,0 +1,232 @@
+package deptree
+
+// NodeIterator yields the ids of the nodes one by one. It's not safe for concurrent use.
+type NodeIterator struct {
+	next func() (string, bool)
+	id   string
+}
+
+// Next advances the iterator to the next node. It returns false if there are no more nodes.
+func (it *NodeIterator) Next() bool {
+	id, ok := it.next()
+	it.id = id
+	return ok
+}
+
+// Id returns the id of the current node. It's valid after Next returned true.
+func (it *NodeIterator) Id() string {
+	return it.id
+}
+
+// IterAsc returns an iterator over the nodes required by the tops in the order of ListAsc. With the default ordering
+// and no cycles the nodes are produced as they are visited, so the whole list is never allocated. Otherwise the order
+// is computed upfront.
+func (dt *DepTree) IterAsc(top ...string) *NodeIterator {
+	if dt.ordering != OrderDepthFirst || len(dt.groups) > 0 {
+		return sliceIterator(dt.ListAsc(top...))
+	}
+	return &NodeIterator{next: postorderStream(dt.lookup, top)}
+}
+
+// IterDesc returns an iterator over the nodes required by the tops in descending order, a node comes before its
+// dependencies. With the default ordering and no cycles the nodes are produced as they are ready, the order is valid,
+// but it may differ from ListDesc. Otherwise the order of ListDesc is computed upfront.
+func (dt *DepTree) IterDesc(top ...string) *NodeIterator {
+	if dt.ordering != OrderDepthFirst || len(dt.groups) > 0 {
+		return sliceIterator(dt.ListDesc(top...))
+	}
+	return &NodeIterator{next: dt.dependentsFirst(top)}
+}
+
+// Walk calls the visit function for the nodes required by the tops in the order of IterAsc. The walk stops when
+// the function returns false.
+func (dt *DepTree) Walk(top []string, visit func(node string) bool) {
+	for it := dt.IterAsc(top...); it.Next(); {
+		if !visit(it.Id()) {
+			return
+		}
+	}
+}
+
+// WalkDesc calls the visit function for the nodes required by the tops in the order of IterDesc. The walk stops when
+// the function returns false.
+func (dt *DepTree) WalkDesc(top []string, visit func(node string) bool) {
+	for it := dt.IterDesc(top...); it.Next(); {
+		if !visit(it.Id()) {
+			return
+		}
+	}
+}
+
+func sliceIterator(ids []string) *NodeIterator {
+	return &NodeIterator{next: func() (string, bool) {
+		if len(ids) == 0 {
+			return "", false
+		}
+		id := ids[0]
+		ids = ids[1:]
+		return id, true
+	}}
+}
+
+// dependentsFirst returns a function producing the nodes required by the tops, every node after all nodes requiring
+// it. Missing nodes are skipped.
+func (dt *DepTree) dependentsFirst(top []string) func() (string, bool) {
+	var waiting map[string]int
+	var ready []string
+	return func() (string, bool) {
+		if waiting == nil {
+			waiting = make(map[string]int)
+			stack := make([]string, 0, len(top))
+			for _, node := range top {
+				if _, ok := dt.deps[node]; ok {
+					stack = append(stack, node)
+				}
+			}
+			for len(stack) > 0 {
+				node := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if _, ok := waiting[node]; ok {
+					continue
+				}
+				waiting[node] = 0
+				for _, dep := range dt.deps[node] {
+					if _, ok := dt.deps[dep]; ok {
+						stack = append(stack, dep)
+					}
+				}
+			}
+			for node := range waiting {
+				for _, dep := range dt.deps[node] {
+					if _, ok := waiting[dep]; ok {
+						waiting[dep]++
+					}
+				}
+			}
+			for i := len(top) - 1; i >= 0; i-- {
+				if count, ok := waiting[top[i]]; ok && count == 0 {
+					waiting[top[i]] = -1
+					ready = append(ready, top[i])
+				}
+			}
+		}
+		if len(ready) == 0 {
+			return "", false
+		}
+		node := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		deps := dt.deps[node]
+		for i := len(deps) - 1; i >= 0; i-- {
+			if count, ok := waiting[deps[i]]; ok {
+				if waiting[deps[i]] = count - 1; count == 1 {
+					ready = append(ready, deps[i])
+				}
+			}
+		}
+		return node, true
+	}
+}
+
+// NIterator yields the nodes of an NDepTree one by one. It's not safe for concurrent use.
+type NIterator[N Node] struct {
+	it    *NodeIterator
+	nodes map[string]N
+}
+
+// Next advances the iterator to the next node. It returns false if there are no more nodes.
+func (it *NIterator[N]) Next() bool {
+	return it.it.Next()
+}
+
+// Node returns the current node. It's valid after Next returned true.
+func (it *NIterator[N]) Node() N {
+	return it.nodes[it.it.Id()]
+}
+
+// IterAsc returns an iterator over the nodes in the order of ListAsc. See DepTree.IterAsc for more details.
+func (dt *NDepTree[N]) IterAsc(top ...N) *NIterator[N] {
+	return dt.IterAscStr(dt.stringify(top)...)
+}
+
+// IterAscStr takes strings representing node ids. See IterAsc for more details.
+func (dt *NDepTree[N]) IterAscStr(top ...string) *NIterator[N] {
+	return &NIterator[N]{it: dt.tree.IterAsc(top...), nodes: dt.nodes}
+}
+
+// IterDesc returns an iterator over the nodes in descending order. See DepTree.IterDesc for more details.
+func (dt *NDepTree[N]) IterDesc(top ...N) *NIterator[N] {
+	return dt.IterDescStr(dt.stringify(top)...)
+}
+
+// IterDescStr takes strings representing node ids. See IterDesc for more details.
+func (dt *NDepTree[N]) IterDescStr(top ...string) *NIterator[N] {
+	return &NIterator[N]{it: dt.tree.IterDesc(top...), nodes: dt.nodes}
+}
+
+// Walk calls the visit function for the nodes in the order of IterAsc. The walk stops when the function returns
+// false.
+func (dt *NDepTree[N]) Walk(top []N, visit func(node N) bool) {
+	dt.WalkStr(dt.stringify(top), visit)
+}
+
+// WalkStr takes strings representing node ids. See Walk for more details.
+func (dt *NDepTree[N]) WalkStr(top []string, visit func(node N) bool) {
+	dt.tree.Walk(top, func(id string) bool {
+		return visit(dt.nodes[id])
+	})
+}
+
+// WalkDesc calls the visit function for the nodes in the order of IterDesc. The walk stops when the function returns
+// false.
+func (dt *NDepTree[N]) WalkDesc(top []N, visit func(node N) bool) {
+	dt.WalkDescStr(dt.stringify(top), visit)
+}
+
+// WalkDescStr takes strings representing node ids. See WalkDesc for more details.
+func (dt *NDepTree[N]) WalkDescStr(top []string, visit func(node N) bool) {
+	dt.tree.WalkDesc(top, func(id string) bool {
+		return visit(dt.nodes[id])
+	})
+}
+
+// IterAsc returns an iterator over the nodes in the order of ListAsc. See DepTree.IterAsc for more details.
+func (dt *IDepTree) IterAsc(top ...Node) *NIterator[Node] {
+	return (*NDepTree[Node])(dt).IterAsc(top...)
+}
+
+// IterAscStr takes strings representing node ids. See IterAsc for more details.
+func (dt *IDepTree) IterAscStr(top ...string) *NIterator[Node] {
+	return (*NDepTree[Node])(dt).IterAscStr(top...)
+}
+
+// IterDesc returns an iterator over the nodes in descending order. See DepTree.IterDesc for more details.
+func (dt *IDepTree) IterDesc(top ...Node) *NIterator[Node] {
+	return (*NDepTree[Node])(dt).IterDesc(top...)
+}
+
+// IterDescStr takes strings representing node ids. See IterDesc for more details.
+func (dt *IDepTree) IterDescStr(top ...string) *NIterator[Node] {
+	return (*NDepTree[Node])(dt).IterDescStr(top...)
+}
+
+// Walk calls the visit function for the nodes in the order of IterAsc. The walk stops when the function returns
+// false.
+func (dt *IDepTree) Walk(top []Node, visit func(node Node) bool) {
+	(*NDepTree[Node])(dt).Walk(top, visit)
+}
+
+// WalkStr takes strings representing node ids. See Walk for more details.
+func (dt *IDepTree) WalkStr(top []string, visit func(node Node) bool) {
+	(*NDepTree[Node])(dt).WalkStr(top, visit)
+}
+
+// WalkDesc calls the visit function for the nodes in the order of IterDesc. The walk stops when the function returns
+// false.
+func (dt *IDepTree) WalkDesc(top []Node, visit func(node Node) bool) {
+	(*NDepTree[Node])(dt).WalkDesc(top, visit)
+}
+
+// WalkDescStr takes strings representing node ids. See WalkDesc for more details.
+func (dt *IDepTree) WalkDescStr(top []string, visit func(node Node) bool) {
+	(*NDepTree[Node])(dt).WalkDescStr(top, visit)
+}
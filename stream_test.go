@@ -0,0 +1,123 @@
+package deptree
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func collect(it *NodeIterator) []string {
+	result := make([]string, 0)
+	for it.Next() {
+		result = append(result, it.Id())
+	}
+	return result
+}
+
+func TestDepTree_IterRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < 100; i++ {
+		deps := randomDeps(rng, 10)
+		builder := NewDepTreeBuilder()
+		for node, ds := range deps {
+			builder.AddDeps(node, ds...)
+		}
+		tree, err := builder.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		top := sortedKeys(deps)[:rng.Intn(len(deps))]
+		top = append(top, "missing")
+		want := tree.ListAsc(top...)
+		if got := collect(tree.IterAsc(top...)); !reflect.DeepEqual(got, want) {
+			t.Fatalf("IterAsc() = %v, want %v", got, want)
+		}
+		got := collect(tree.IterDesc(top...))
+		required := make(map[string][]string)
+		for _, node := range want {
+			required[node] = deps[node]
+		}
+		if !validOrder(required, reversed(got)) {
+			t.Fatalf("IterDesc() = %v is not a valid descending order of %v", got, required)
+		}
+	}
+}
+
+func TestDepTree_IterOrdering(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "b", "a")
+	builder.AddDeps("a")
+	builder.AddDeps("b")
+	builder.SetOrdering(OrderCanonical)
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := collect(tree.IterAsc("app")), []string{"a", "b", "app"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterAsc() = %v, want %v", got, want)
+	}
+	if got, want := collect(tree.IterDesc("app")), []string{"app", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterDesc() = %v, want %v", got, want)
+	}
+}
+
+func TestDepTree_Walk(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "api", "ui")
+	builder.AddDeps("api", "db")
+	builder.AddDeps("ui")
+	builder.AddDeps("db")
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make([]string, 0)
+	tree.Walk([]string{"app"}, func(node string) bool {
+		got = append(got, node)
+		return node != "db"
+	})
+	if want := []string{"ui", "db"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+	got = got[:0]
+	tree.WalkDesc([]string{"app", "api"}, func(node string) bool {
+		got = append(got, node)
+		return true
+	})
+	if want := []string{"app", "api", "db", "ui"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WalkDesc() = %v, want %v", got, want)
+	}
+}
+
+func TestNDepTree_Walk(t *testing.T) {
+	nodes := []*testNode{
+		{nodeId: "a", deps: []string{"b", "c"}},
+		{nodeId: "b", deps: []string{"c"}},
+		{nodeId: "c"},
+	}
+	builder := NewNDepTreeBuilder[*testNode]()
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make([]*testNode, 0)
+	for it := tree.IterDesc(nodes[0]); it.Next(); {
+		got = append(got, it.Node())
+	}
+	if want := []*testNode{nodes[0], nodes[1], nodes[2]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IterDesc() = %v, want %v", got, want)
+	}
+	ids := make([]string, 0)
+	tree.WalkStr([]string{"a"}, func(node *testNode) bool {
+		ids = append(ids, node.NodeId())
+		return len(ids) < 2
+	})
+	sort.Strings(ids)
+	if want := []string{"b", "c"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("WalkStr() = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,42 @@
+package deptree
+
+// Subtree returns a new dependency tree containing only the nodes required by the given tops, including the tops.
+func (dt *DepTree) Subtree(top ...string) *DepTree {
+	deps := make(map[string][]string)
+	for _, node := range dt.ListAsc(top...) {
+		deps[node] = make([]string, 0, len(dt.deps[node]))
+		for _, dep := range dt.deps[node] {
+			if _, ok := dt.deps[dep]; ok {
+				deps[node] = append(deps[node], dep)
+			}
+		}
+	}
+	return dt.derive(deps)
+}
+
+// Subtree returns a new dependency tree containing only the nodes required by the given tops, including the tops.
+func (dt *NDepTree[N]) Subtree(top ...N) *NDepTree[N] {
+	return dt.SubtreeStr(dt.stringify(top)...)
+}
+
+// SubtreeStr takes strings representing node ids. See Subtree for more details.
+func (dt *NDepTree[N]) SubtreeStr(top ...string) *NDepTree[N] {
+	tree := dt.tree.Subtree(top...)
+	nodes := make(map[string]N, len(tree.deps))
+	for id := range tree.deps {
+		if node, ok := dt.nodes[id]; ok {
+			nodes[id] = node
+		}
+	}
+	return &NDepTree[N]{nodes: nodes, tree: tree}
+}
+
+// Subtree returns a new dependency tree containing only the nodes required by the given tops, including the tops.
+func (dt *IDepTree) Subtree(top ...Node) *IDepTree {
+	return (*IDepTree)((*NDepTree[Node])(dt).Subtree(top...))
+}
+
+// SubtreeStr takes strings representing node ids. See Subtree for more details.
+func (dt *IDepTree) SubtreeStr(top ...string) *IDepTree {
+	return (*IDepTree)((*NDepTree[Node])(dt).SubtreeStr(top...))
+}
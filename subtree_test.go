@@ -0,0 +1,73 @@
+package deptree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDepTree_Subtree(t *testing.T) {
+	dt := &DepTree{
+		deps:      map[string][]string{"a": {"b", "x"}, "b": {"c"}, "c": {}, "d": {"c"}},
+		conflicts: map[string][]string{"b": {"d"}, "d": {"b"}},
+		costs:     map[string]float64{"a": 2, "d": 3},
+	}
+	tests := []struct {
+		name string
+		top  []string
+		want *DepTree
+	}{
+		{
+			name: "single top",
+			top:  []string{"a"},
+			want: &DepTree{
+				deps:      map[string][]string{"a": {"b"}, "b": {"c"}, "c": {}},
+				conflicts: map[string][]string{"b": {"d"}},
+				costs:     map[string]float64{"a": 2},
+			},
+		},
+		{
+			name: "many tops",
+			top:  []string{"b", "d"},
+			want: &DepTree{
+				deps:      map[string][]string{"b": {"c"}, "c": {}, "d": {"c"}},
+				conflicts: map[string][]string{"b": {"d"}, "d": {"b"}},
+				costs:     map[string]float64{"d": 3},
+			},
+		},
+		{
+			name: "not existent top",
+			top:  []string{"x"},
+			want: &DepTree{deps: map[string][]string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dt.Subtree(tt.top...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subtree() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDepTree_Subtree(t *testing.T) {
+	builder := NewIDepTreeBuilder()
+	nodes := []*testNode{
+		{nodeId: "a", deps: []string{"b"}},
+		{nodeId: "b"},
+		{nodeId: "c", deps: []string{"b"}},
+	}
+	for _, node := range nodes {
+		builder.AddNode(node)
+	}
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sub := tree.Subtree(nodes[2])
+	if got, want := sortedKeys(sub.nodes), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Subtree() nodes = %v, want %v", got, want)
+	}
+	if got := sub.ListAscStr("a"); len(got) != 0 {
+		t.Errorf("ListAscStr() = %v, want empty", got)
+	}
+}
@@ -0,0 +1,127 @@
+package deptree
+
+import "sync"
+
+// SyncDepTreeBuilder is a DepTreeBuilder safe for concurrent use. The built trees are independent of the builder and
+// safe for concurrent readers, so the builder may be modified while the built trees are in use.
+type SyncDepTreeBuilder struct {
+	mu      sync.Mutex
+	builder *DepTreeBuilder
+}
+
+// NewSyncDepTreeBuilder returns a new dependency tree builder safe for concurrent use.
+func NewSyncDepTreeBuilder() *SyncDepTreeBuilder {
+	return &SyncDepTreeBuilder{builder: NewDepTreeBuilder()}
+}
+
+// AddDeps adds dependencies to the dependency tree. See DepTreeBuilder.AddDeps for more details.
+func (dtb *SyncDepTreeBuilder) AddDeps(node string, deps ...string) {
+	dtb.Do(func(b *DepTreeBuilder) { b.AddDeps(node, deps...) })
+}
+
+// AddConflicts declares the conflicting nodes. See DepTreeBuilder.AddConflicts for more details.
+func (dtb *SyncDepTreeBuilder) AddConflicts(node string, conflicts ...string) {
+	dtb.Do(func(b *DepTreeBuilder) { b.AddConflicts(node, conflicts...) })
+}
+
+// SetCost sets the cost of the node. See DepTreeBuilder.SetCost for more details.
+func (dtb *SyncDepTreeBuilder) SetCost(node string, cost float64) {
+	dtb.Do(func(b *DepTreeBuilder) { b.SetCost(node, cost) })
+}
+
+// ReplaceDeps replaces all dependencies of the node. See DepTreeBuilder.ReplaceDeps for more details.
+func (dtb *SyncDepTreeBuilder) ReplaceDeps(node string, deps ...string) {
+	dtb.Do(func(b *DepTreeBuilder) { b.ReplaceDeps(node, deps...) })
+}
+
+// RemoveDep removes the dependency of the node. See DepTreeBuilder.RemoveDep for more details.
+func (dtb *SyncDepTreeBuilder) RemoveDep(node, dep string) (err error) {
+	dtb.Do(func(b *DepTreeBuilder) { err = b.RemoveDep(node, dep) })
+	return err
+}
+
+// RemoveNode removes the node. See DepTreeBuilder.RemoveNode for more details.
+func (dtb *SyncDepTreeBuilder) RemoveNode(node string, mode RemoveMode) (err error) {
+	dtb.Do(func(b *DepTreeBuilder) { err = b.RemoveNode(node, mode) })
+	return err
+}
+
+// ForceIntegrity adds missing nodes to the builder. See DepTreeBuilder.ForceIntegrity for more details.
+func (dtb *SyncDepTreeBuilder) ForceIntegrity() {
+	dtb.Do(func(b *DepTreeBuilder) { b.ForceIntegrity() })
+}
+
+// Build builds a dependency tree. See DepTreeBuilder.Build for more details.
+func (dtb *SyncDepTreeBuilder) Build() (tree *DepTree, err error) {
+	dtb.Do(func(b *DepTreeBuilder) { tree, err = b.Build() })
+	return tree, err
+}
+
+// Do calls the function with the wrapped builder while holding the lock. It gives access to the methods which are not
+// wrapped. The builder must not be used after the function returns.
+func (dtb *SyncDepTreeBuilder) Do(fn func(b *DepTreeBuilder)) {
+	dtb.mu.Lock()
+	defer dtb.mu.Unlock()
+	fn(dtb.builder)
+}
+
+// SyncNDepTreeBuilder is a NDepTreeBuilder safe for concurrent use. The built trees are independent of the builder and
+// safe for concurrent readers, so the builder may be modified while the built trees are in use.
+type SyncNDepTreeBuilder[N Node] struct {
+	mu      sync.Mutex
+	builder *NDepTreeBuilder[N]
+}
+
+// NewSyncNDepTreeBuilder returns a new NDepTreeBuilder safe for concurrent use.
+func NewSyncNDepTreeBuilder[N Node]() *SyncNDepTreeBuilder[N] {
+	return &SyncNDepTreeBuilder[N]{builder: NewNDepTreeBuilder[N]()}
+}
+
+// AddNode adds a node to the builder. See NDepTreeBuilder.AddNode for more details.
+func (dtb *SyncNDepTreeBuilder[N]) AddNode(node N) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { b.AddNode(node) })
+}
+
+// TryAddNode adds a node to the builder. See NDepTreeBuilder.TryAddNode for more details.
+func (dtb *SyncNDepTreeBuilder[N]) TryAddNode(node N) (err error) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { err = b.TryAddNode(node) })
+	return err
+}
+
+// SetDuplicatePolicy sets the duplicate policy. See NDepTreeBuilder.SetDuplicatePolicy for more details.
+func (dtb *SyncNDepTreeBuilder[N]) SetDuplicatePolicy(policy DuplicatePolicy) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { b.SetDuplicatePolicy(policy) })
+}
+
+// RemoveNode removes the node with the given id. See NDepTreeBuilder.RemoveNode for more details.
+func (dtb *SyncNDepTreeBuilder[N]) RemoveNode(id string, mode RemoveMode) (err error) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { err = b.RemoveNode(id, mode) })
+	return err
+}
+
+// RemoveDep removes the dependency of the node with the given id. See NDepTreeBuilder.RemoveDep for more details.
+func (dtb *SyncNDepTreeBuilder[N]) RemoveDep(id, dep string) (err error) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { err = b.RemoveDep(id, dep) })
+	return err
+}
+
+// ReplaceDeps replaces all dependencies of the node with the given id. See NDepTreeBuilder.ReplaceDeps for more
+// details.
+func (dtb *SyncNDepTreeBuilder[N]) ReplaceDeps(id string, deps ...string) (err error) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { err = b.ReplaceDeps(id, deps...) })
+	return err
+}
+
+// Build builds a dependency tree. See NDepTreeBuilder.Build for more details.
+func (dtb *SyncNDepTreeBuilder[N]) Build() (tree *NDepTree[N], err error) {
+	dtb.Do(func(b *NDepTreeBuilder[N]) { tree, err = b.Build() })
+	return tree, err
+}
+
+// Do calls the function with the wrapped builder while holding the lock. It gives access to the methods which are not
+// wrapped. The builder must not be used after the function returns.
+func (dtb *SyncNDepTreeBuilder[N]) Do(fn func(b *NDepTreeBuilder[N])) {
+	dtb.mu.Lock()
+	defer dtb.mu.Unlock()
+	fn(dtb.builder)
+}
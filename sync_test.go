@@ -0,0 +1,62 @@
+package deptree
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSyncDepTreeBuilder_Concurrent(t *testing.T) {
+	builder := NewSyncDepTreeBuilder()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			builder.AddDeps(fmt.Sprint(i), fmt.Sprint(i+1))
+			builder.SetCost(fmt.Sprint(i), float64(i))
+			if _, err := builder.Build(); err == nil {
+				t.Errorf("Build() error = nil, want integrity error")
+			}
+		}(i)
+	}
+	wg.Wait()
+	builder.ForceIntegrity()
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(tree.ListAsc("0")); got != 51 {
+		t.Errorf("ListAsc() length = %d, want 51", got)
+	}
+}
+
+func TestSyncNDepTreeBuilder_ConcurrentReaders(t *testing.T) {
+	builder := NewSyncNDepTreeBuilder[*testNode]()
+	builder.AddNode(&testNode{nodeId: "a", deps: []string{"b"}})
+	builder.AddNode(&testNode{nodeId: "b"})
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := tree.ListAscStr("a")
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			builder.AddNode(&testNode{nodeId: fmt.Sprint(i), deps: []string{"a"}})
+			builder.RemoveNode(fmt.Sprint(i), RemoveStrict)
+		}(i)
+		go func() {
+			defer wg.Done()
+			if got := tree.ListAscStr("a"); !reflect.DeepEqual(got, want) {
+				t.Errorf("ListAscStr() = %v, want %v", got, want)
+			}
+			tree.CriticalPathStr("a")
+			tree.tree.DependsOn("a", "b")
+		}()
+	}
+	wg.Wait()
+}
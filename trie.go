@@ -0,0 +1,206 @@
+package deptree
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+const (
+	trieBits  = 5
+	trieDepth = 64 / trieBits
+)
+
+// trie is a persistent hash array mapped trie from node ids to lists of node ids. Modifications return a new trie
+// sharing all unchanged nodes with the old one, the old trie stays valid. The lists must not be modified.
+type trie struct {
+	root *trieNode
+	size int
+}
+
+type trieEntry struct {
+	key   string
+	hash  uint64
+	value []string
+}
+
+type trieSlot struct {
+	entry *trieEntry
+	child *trieNode
+}
+
+type trieNode struct {
+	bitmap uint32
+	slots  []trieSlot
+	// bucket keeps colliding entries at the deepest level
+	bucket []trieEntry
+}
+
+func trieHash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+func (t trie) get(key string) ([]string, bool) {
+	n, h := t.root, trieHash(key)
+	for depth := 0; n != nil; depth++ {
+		if depth == trieDepth {
+			for _, e := range n.bucket {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			return nil, false
+		}
+		bit := uint32(1) << ((h >> (depth * trieBits)) & 31)
+		if n.bitmap&bit == 0 {
+			return nil, false
+		}
+		slot := n.slots[bits.OnesCount32(n.bitmap&(bit-1))]
+		if slot.entry != nil {
+			if slot.entry.key == key {
+				return slot.entry.value, true
+			}
+			return nil, false
+		}
+		n = slot.child
+	}
+	return nil, false
+}
+
+func (t trie) set(key string, value []string) trie {
+	h := trieHash(key)
+	root, added := t.root.set(0, h, &trieEntry{key: key, hash: h, value: value})
+	if added {
+		return trie{root: root, size: t.size + 1}
+	}
+	return trie{root: root, size: t.size}
+}
+
+func (t trie) delete(key string) trie {
+	root, deleted := t.root.delete(0, trieHash(key), key)
+	if deleted {
+		return trie{root: root, size: t.size - 1}
+	}
+	return t
+}
+
+// each calls the function for every entry of the trie.
+func (t trie) each(fn func(key string, value []string)) {
+	var walk func(n *trieNode)
+	walk = func(n *trieNode) {
+		if n == nil {
+			return
+		}
+		for _, e := range n.bucket {
+			fn(e.key, e.value)
+		}
+		for _, slot := range n.slots {
+			if slot.entry != nil {
+				fn(slot.entry.key, slot.entry.value)
+			} else {
+				walk(slot.child)
+			}
+		}
+	}
+	walk(t.root)
+}
+
+func (n *trieNode) set(depth int, h uint64, entry *trieEntry) (*trieNode, bool) {
+	if n == nil {
+		n = &trieNode{}
+	}
+	if depth == trieDepth {
+		bucket := make([]trieEntry, 0, len(n.bucket)+1)
+		added := true
+		for _, e := range n.bucket {
+			if e.key == entry.key {
+				added = false
+				continue
+			}
+			bucket = append(bucket, e)
+		}
+		return &trieNode{bucket: append(bucket, *entry)}, added
+	}
+	bit := uint32(1) << ((h >> (depth * trieBits)) & 31)
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		slots := make([]trieSlot, len(n.slots)+1)
+		copy(slots, n.slots[:pos])
+		slots[pos] = trieSlot{entry: entry}
+		copy(slots[pos+1:], n.slots[pos:])
+		return &trieNode{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+	slots := make([]trieSlot, len(n.slots))
+	copy(slots, n.slots)
+	slot := n.slots[pos]
+	added := true
+	switch {
+	case slot.entry != nil && slot.entry.key == entry.key:
+		slots[pos] = trieSlot{entry: entry}
+		added = false
+	case slot.entry != nil:
+		child, _ := (*trieNode)(nil).set(depth+1, slot.entry.hash, slot.entry)
+		child, _ = child.set(depth+1, h, entry)
+		slots[pos] = trieSlot{child: child}
+	default:
+		var child *trieNode
+		child, added = slot.child.set(depth+1, h, entry)
+		slots[pos] = trieSlot{child: child}
+	}
+	return &trieNode{bitmap: n.bitmap, slots: slots}, added
+}
+
+func (n *trieNode) delete(depth int, h uint64, key string) (*trieNode, bool) {
+	if n == nil {
+		return nil, false
+	}
+	if depth == trieDepth {
+		bucket := make([]trieEntry, 0, len(n.bucket))
+		for _, e := range n.bucket {
+			if e.key != key {
+				bucket = append(bucket, e)
+			}
+		}
+		if len(bucket) == len(n.bucket) {
+			return n, false
+		}
+		if len(bucket) == 0 {
+			return nil, true
+		}
+		return &trieNode{bucket: bucket}, true
+	}
+	bit := uint32(1) << ((h >> (depth * trieBits)) & 31)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	slot := n.slots[pos]
+	var replacement *trieSlot
+	if slot.entry != nil {
+		if slot.entry.key != key {
+			return n, false
+		}
+	} else {
+		child, deleted := slot.child.delete(depth+1, h, key)
+		if !deleted {
+			return n, false
+		}
+		if child != nil {
+			replacement = &trieSlot{child: child}
+		}
+	}
+	if replacement != nil {
+		slots := make([]trieSlot, len(n.slots))
+		copy(slots, n.slots)
+		slots[pos] = *replacement
+		return &trieNode{bitmap: n.bitmap, slots: slots}, true
+	}
+	if len(n.slots) == 1 {
+		return nil, true
+	}
+	slots := make([]trieSlot, 0, len(n.slots)-1)
+	slots = append(slots, n.slots[:pos]...)
+	slots = append(slots, n.slots[pos+1:]...)
+	return &trieNode{bitmap: n.bitmap &^ bit, slots: slots}, true
+}
@@ -0,0 +1,82 @@
+package deptree
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTrie(t *testing.T) {
+	versions := []trie{{}}
+	for i := 0; i < 2000; i++ {
+		versions = append(versions, versions[len(versions)-1].set(fmt.Sprint(i), []string{fmt.Sprint(i)}))
+	}
+	for v, tr := range versions {
+		if tr.size != v {
+			t.Fatalf("size = %d, want %d", tr.size, v)
+		}
+	}
+	full := versions[len(versions)-1]
+	for i := 0; i < 2000; i++ {
+		if got, ok := full.get(fmt.Sprint(i)); !ok || !reflect.DeepEqual(got, []string{fmt.Sprint(i)}) {
+			t.Fatalf("get(%d) = %v, %v", i, got, ok)
+		}
+		if _, ok := versions[i].get(fmt.Sprint(i)); ok {
+			t.Fatalf("get(%d) found in older version", i)
+		}
+	}
+	replaced := full.set("5", []string{"x"})
+	if got, _ := replaced.get("5"); replaced.size != 2000 || !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("set() replaced = %v, size %d", got, replaced.size)
+	}
+	if got, _ := full.get("5"); !reflect.DeepEqual(got, []string{"5"}) {
+		t.Errorf("set() modified older version: %v", got)
+	}
+	deleted := full
+	for i := 0; i < 2000; i += 2 {
+		deleted = deleted.delete(fmt.Sprint(i))
+	}
+	deleted = deleted.delete("missing")
+	keys := make([]string, 0)
+	deleted.each(func(key string, value []string) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	if len(keys) != 1000 || deleted.size != 1000 {
+		t.Fatalf("delete() left %d keys, size %d", len(keys), deleted.size)
+	}
+	for _, k := range keys {
+		var i int
+		fmt.Sscan(k, &i)
+		if i%2 == 0 {
+			t.Fatalf("delete() left %s", k)
+		}
+	}
+	if _, ok := full.get("0"); !ok {
+		t.Errorf("delete() modified older version")
+	}
+}
+
+func TestTrieNode_Collisions(t *testing.T) {
+	var n *trieNode
+	n, _ = n.set(0, 7, &trieEntry{key: "a", hash: 7, value: []string{"1"}})
+	n, _ = n.set(0, 7, &trieEntry{key: "b", hash: 7, value: []string{"2"}})
+	for depth, child := 0, n; depth < trieDepth; depth++ {
+		if len(child.slots) != 1 {
+			t.Fatalf("slots at depth %d = %d, want 1", depth, len(child.slots))
+		}
+		child = child.slots[0].child
+		if depth == trieDepth-1 && len(child.bucket) != 2 {
+			t.Fatalf("bucket = %v, want 2 entries", child.bucket)
+		}
+	}
+	n, deleted := n.delete(0, 7, "a")
+	if !deleted || n == nil {
+		t.Fatalf("delete() = %v, %v", n, deleted)
+	}
+	n, deleted = n.delete(0, 7, "b")
+	if !deleted || n != nil {
+		t.Errorf("delete() = %v, %v, want empty", n, deleted)
+	}
+}
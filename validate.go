@@ -0,0 +1,109 @@
+package deptree
+
+import (
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidOrder = fmt.Errorf("invalid order")
+
+// ValidationError is returned when a sequence of nodes is not a valid ascending order of the tree.
+type ValidationError struct {
+	// Violated are the dependencies listed after the nodes requiring them.
+	Violated []Edge
+	// Missing are the dependencies required directly or transitively by the listed nodes, but not listed.
+	Missing []Edge
+	// Unknown are the listed ids that don't exist in the tree.
+	Unknown []string
+	// Repeated are the ids listed more than once. The first occurrence is used to check the order.
+	Repeated []string
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0)
+	for _, edge := range e.Violated {
+		problems = append(problems, fmt.Sprintf("\"%s\" listed after \"%s\" requiring it", edge.To, edge.From))
+	}
+	for _, edge := range e.Missing {
+		problems = append(problems, fmt.Sprintf("\"%s\" required by \"%s\" is missing", edge.To, edge.From))
+	}
+	for _, id := range e.Unknown {
+		problems = append(problems, fmt.Sprintf("\"%s\" is unknown", id))
+	}
+	for _, id := range e.Repeated {
+		problems = append(problems, fmt.Sprintf("\"%s\" is repeated", id))
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidOrder, strings.Join(problems, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidOrder
+}
+
+// Validate checks whether the order is a valid ascending order, like the one returned by ListAsc. Every dependency
+// of the listed nodes must be listed before the node requiring it. The dependencies between the nodes of the same cycle
+// condensed by CondenseCycles are ignored, those nodes may be listed in any order. All problems found are returned in
+// a ValidationError.
+func (dt *DepTree) Validate(order []string) error {
+	e := &ValidationError{
+		Violated: make([]Edge, 0),
+		Missing:  make([]Edge, 0),
+		Unknown:  make([]string, 0),
+		Repeated: make([]string, 0),
+	}
+	position := make(map[string]int, len(order))
+	for i, node := range order {
+		if _, ok := position[node]; ok {
+			if !contains(e.Repeated, node) {
+				e.Repeated = append(e.Repeated, node)
+			}
+			continue
+		}
+		position[node] = i
+		if _, ok := dt.deps[node]; !ok {
+			e.Unknown = append(e.Unknown, node)
+		}
+	}
+	required := make(map[string]bool)
+	for _, node := range dt.ListAsc(order...) {
+		required[node] = true
+	}
+	for _, node := range sortedKeys(required) {
+		nodePos, nodeListed := position[node]
+		for _, dep := range dt.deps[node] {
+			depPos, depListed := position[dep]
+			switch {
+			case !depListed:
+				e.Missing = append(e.Missing, Edge{From: node, To: dep})
+			case nodeListed && depPos > nodePos && dt.representative(node) != dt.representative(dep):
+				e.Violated = append(e.Violated, Edge{From: node, To: dep})
+			}
+		}
+	}
+	sortEdges(e.Violated)
+	sortEdges(e.Missing)
+	if len(e.Violated)+len(e.Missing)+len(e.Unknown)+len(e.Repeated) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks whether the order of the nodes is a valid ascending order. See DepTree.Validate for more details.
+func (dt *NDepTree[N]) Validate(order []N) error {
+	return dt.ValidateStr(dt.stringify(order))
+}
+
+// ValidateStr takes strings representing node ids. See Validate for more details.
+func (dt *NDepTree[N]) ValidateStr(order []string) error {
+	return dt.tree.Validate(order)
+}
+
+// Validate checks whether the order of the nodes is a valid ascending order. See DepTree.Validate for more details.
+func (dt *IDepTree) Validate(order []Node) error {
+	return (*NDepTree[Node])(dt).Validate(order)
+}
+
+// ValidateStr takes strings representing node ids. See Validate for more details.
+func (dt *IDepTree) ValidateStr(order []string) error {
+	return (*NDepTree[Node])(dt).ValidateStr(order)
+}
@@ -0,0 +1,95 @@
+package deptree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDepTree_Validate(t *testing.T) {
+	builder := NewDepTreeBuilder()
+	builder.AddDeps("app", "api", "ui")
+	builder.AddDeps("api", "db")
+	builder.AddDeps("ui")
+	builder.AddDeps("db")
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name  string
+		order []string
+		want  *ValidationError
+	}{
+		{name: "valid", order: []string{"db", "ui", "api", "app"}},
+		{name: "valid part", order: []string{"db", "api"}},
+		{name: "empty", order: []string{}},
+		{
+			name:  "violated",
+			order: []string{"api", "db", "app", "ui"},
+			want: &ValidationError{
+				Violated: []Edge{{From: "api", To: "db"}, {From: "app", To: "ui"}},
+				Missing:  []Edge{},
+				Unknown:  []string{},
+				Repeated: []string{},
+			},
+		},
+		{
+			name:  "missing",
+			order: []string{"ui", "app"},
+			want: &ValidationError{
+				Violated: []Edge{},
+				Missing:  []Edge{{From: "api", To: "db"}, {From: "app", To: "api"}},
+				Unknown:  []string{},
+				Repeated: []string{},
+			},
+		},
+		{
+			name:  "unknown and repeated",
+			order: []string{"ui", "cache", "ui"},
+			want: &ValidationError{
+				Violated: []Edge{},
+				Missing:  []Edge{},
+				Unknown:  []string{"cache"},
+				Repeated: []string{"ui"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tree.Validate(tt.order)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOrder) {
+				t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidOrder)
+			}
+			var got *ValidationError
+			if !errors.As(err, &got) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNDepTree_Validate(t *testing.T) {
+	a := &testNode{nodeId: "a", deps: []string{"b"}}
+	b := &testNode{nodeId: "b"}
+	builder := NewNDepTreeBuilder[*testNode]()
+	builder.AddNode(a)
+	builder.AddNode(b)
+	tree, err := builder.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tree.Validate([]*testNode{b, a}); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	want := `invalid order: "b" listed after "a" requiring it`
+	if err := tree.Validate([]*testNode{a, b}); err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %v", err, want)
+	}
+}